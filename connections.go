@@ -0,0 +1,189 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+	"github.com/ifzzh/cmd-nse-template/internal/adminrpc"
+)
+
+// connectionsCommand 连接查询子命令名称
+const connectionsCommand = "connections"
+
+// runConnections 执行 connections 子命令
+//
+// 通过管理 gRPC 接口列出运行中实例的连接，或输出指定连接的完整规则，也可以隔离或解除隔离指定连接，
+// 返回进程退出码：
+//   - 0: 查询成功
+//   - 1: 查询失败
+//
+// 调用方使用 SPIFFE Workload API（SPIFFE_ENDPOINT_SOCKET）获取的 SVID 认证。
+//
+// 用法:
+//
+//	cmd-nse-firewall-vpp connections [-socket unix:///var/lib/firewall/admin.sock] [-id <连接 ID> [-quarantine|-release]] [-json]
+func runConnections(args []string) int {
+	flags := flag.NewFlagSet(connectionsCommand, flag.ContinueOnError)
+	socket := flags.String("socket", "unix:///var/lib/firewall/admin.sock", "admin gRPC socket of the running firewall")
+	connID := flags.String("id", "", "print the compiled rules of this connection")
+	quarantine := flags.Bool("quarantine", false, "quarantine the connection given by -id")
+	release := flags.Bool("release", false, "release the connection given by -id from quarantine")
+	asJSON := flags.Bool("json", false, "print the raw JSON response")
+	timeout := flags.Duration("timeout", 10*time.Second, "timeout of the query")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	if (*quarantine || *release) && (*connID == "" || *quarantine == *release) {
+		fmt.Fprintln(os.Stderr, "-quarantine 和 -release 只能选择一个，并且须通过 -id 指定连接")
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	client, closeClient, err := dialAdmin(ctx, *socket)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "连接管理 gRPC 接口失败: %v\n", err)
+		return 1
+	}
+	defer closeClient()
+
+	var result interface{}
+	switch {
+	case *quarantine:
+		result, err = client.QuarantineConnection(ctx, *connID)
+	case *release:
+		result, err = client.ReleaseConnection(ctx, *connID)
+	case *connID != "":
+		result, err = client.GetConnectionRules(ctx, *connID)
+	default:
+		result, err = client.ListConnections(ctx)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "查询失败: %v\n", err)
+		return 1
+	}
+
+	switch v := result.(type) {
+	case *acl.ConnectionRules:
+		if !*asJSON {
+			printConnectionRules(os.Stdout, v)
+			return 0
+		}
+	case []acl.ConnectionInfo:
+		if !*asJSON {
+			printConnections(os.Stdout, v)
+			return 0
+		}
+	case *acl.ConnectionInfo:
+		if !*asJSON {
+			printConnections(os.Stdout, []acl.ConnectionInfo{*v})
+			return 0
+		}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(result)
+	return 0
+}
+
+// dialAdmin 使用 SPIFFE SVID 通过 mTLS 连接管理 gRPC 接口
+func dialAdmin(ctx context.Context, socket string) (*adminrpc.Client, func(), error) {
+	source, err := workloadapi.NewX509Source(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "获取 X509 凭证源失败")
+	}
+	tlsConfig := tlsconfig.MTLSClientConfig(source, source, tlsconfig.AuthorizeAny())
+	tlsConfig.MinVersion = tls.VersionTLS12
+
+	cc, err := grpc.NewClient(socket,
+		grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+		adminrpc.WithCodec(),
+	)
+	if err != nil {
+		_ = source.Close()
+		return nil, nil, errors.Wrapf(err, "连接 %s 失败", socket)
+	}
+	return adminrpc.NewClient(cc), func() {
+		_ = cc.Close()
+		_ = source.Close()
+	}, nil
+}
+
+// printConnections 以表格形式输出连接列表
+func printConnections(out io.Writer, conns []acl.ConnectionInfo) {
+	_, _ = fmt.Fprintf(out, "连接: %d 个\n", len(conns))
+	if len(conns) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CONNECTION\tNSC\tSERVICE\tIFINDEX\tRULE SETS\tINGRESS\tEGRESS\tSTATE")
+	for i := range conns {
+		c := &conns[i]
+		state := "ok"
+		switch {
+		case c.Quarantined:
+			state = "quarantined"
+		case c.Degraded:
+			state = "degraded"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%v\t%v\t%s\n",
+			c.ID, c.NSCName, c.NetworkService, c.SwIfIndex, strings.Join(c.RuleSets, ","), c.Ingress, c.Egress, state)
+	}
+	_ = w.Flush()
+}
+
+// printConnectionRules 以表格形式输出连接的入站和出站规则
+func printConnectionRules(out io.Writer, rules *acl.ConnectionRules) {
+	for _, dir := range []struct {
+		name  string
+		rules []acl.RuleInfo
+	}{{"入站 (ingress)", rules.Ingress}, {"出站 (egress)", rules.Egress}} {
+		_, _ = fmt.Fprintf(out, "%s 规则: %d 条\n", dir.name, len(dir.rules))
+		if len(dir.rules) == 0 {
+			continue
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "POSITION\tRULE\tRULE SET\tACTION\tPROTO\tSRC\tSRC PORTS\tDST\tDST PORTS")
+		for _, r := range dir.rules {
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+				r.Position, r.Name, r.RuleSet, r.Action, r.Proto, r.Src, r.SrcPorts, r.Dst, r.DstPorts)
+		}
+		_ = w.Flush()
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+)
+
+// deadRulesCommand 未命中规则报告子命令名称
+const deadRulesCommand = "dead-rules"
+
+// runDeadRules 执行 dead-rules 子命令
+//
+// 从运行中实例的管理端点获取未命中规则报告并输出，返回进程退出码：
+//   - 0: 没有未命中的规则
+//   - 1: 查询失败
+//   - 2: 存在未命中的规则
+//
+// 用法:
+//
+//	cmd-nse-firewall-vpp dead-rules [-admin localhost:9090] [-window 24h] [-json]
+func runDeadRules(args []string) int {
+	flags := flag.NewFlagSet(deadRulesCommand, flag.ContinueOnError)
+	adminAddr := flags.String("admin", "localhost:9090", "admin HTTP endpoint of the running firewall")
+	window := flags.Duration("window", 0, "time window of the report (default: NSM_ACL_DEAD_RULE_WINDOW of the running firewall)")
+	asJSON := flags.Bool("json", false, "print the raw JSON report")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	report, raw, err := fetchDeadRules(*adminAddr, *window)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "获取未命中规则报告失败: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		_, _ = os.Stdout.Write(raw)
+	} else {
+		printDeadRules(os.Stdout, report)
+	}
+	if len(report.Rules) > 0 {
+		return 2
+	}
+	return 0
+}
+
+// fetchDeadRules 从管理端点获取未命中规则报告
+func fetchDeadRules(adminAddr string, window time.Duration) (*acl.DeadRuleReport, []byte, error) {
+	u := url.URL{Scheme: "http", Host: adminAddr, Path: "/acl/dead-rules"}
+	if window > 0 {
+		u.RawQuery = url.Values{"window": []string{window.String()}}.Encode()
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	rsp, err := client.Get(u.String())
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "请求 %s 失败", u.String())
+	}
+	defer func() { _ = rsp.Body.Close() }()
+
+	raw, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "读取响应失败")
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, nil, errors.Errorf("管理端点返回 %s: %s", rsp.Status, raw)
+	}
+
+	report := new(acl.DeadRuleReport)
+	if err := json.Unmarshal(raw, report); err != nil {
+		return nil, nil, errors.Wrap(err, "解析报告失败")
+	}
+	return report, raw, nil
+}
+
+// printDeadRules 以表格形式输出未命中规则报告
+func printDeadRules(out io.Writer, report *acl.DeadRuleReport) {
+	_, _ = fmt.Fprintf(out, "时间窗口 %s 内未命中的规则: %d 条\n", report.Window, len(report.Rules))
+	if len(report.Rules) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "RULESET\tRULE\tACTION\tLAST HIT\tNOTE")
+	for _, r := range report.Rules {
+		lastHit := "never"
+		if r.LastHit != nil {
+			lastHit = r.LastHit.Format(time.RFC3339)
+		}
+		note := ""
+		if r.Partial {
+			note = fmt.Sprintf("observed only since %s", r.ObservedSince.Format(time.RFC3339))
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.RuleSet, r.Rule, r.Action, lastHit, note)
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl_test
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/govpp/binapi/acl_types"
+	"github.com/networkservicemesh/govpp/binapi/interface_types"
+	"github.com/networkservicemesh/sdk-vpp/pkg/tools/ifindex"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+	"github.com/ifzzh/cmd-nse-template/internal/acl/acltest"
+)
+
+// testSwIfIndex 测试连接的 NSC 侧接口索引
+const testSwIfIndex interface_types.InterfaceIndex = 7
+
+// ifIndexServer 将 testSwIfIndex 作为连接的接口索引存入元数据，代替 memif 等机制元素
+type ifIndexServer struct{}
+
+func (ifIndexServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	ifindex.Store(ctx, metadata.IsClient(ifIndexServer{}), testSwIfIndex)
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (ifIndexServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+// newTestServer 创建使用内存后端的 ACL 服务器链
+func newTestServer(backend *acltest.Backend, policy *acl.Policy, options ...acl.Option) networkservice.NetworkServiceServer {
+	return chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ifIndexServer{},
+		acl.NewServer(nil, policy, append([]acl.Option{acl.WithBackend(backend)}, options...)...),
+	)
+}
+
+// testRequest 创建连接 id 的请求，srcAddrs 为分配给 NSC 的源地址
+func testRequest(id string, srcAddrs ...string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             id,
+			NetworkService: "firewall",
+			Context: &networkservice.ConnectionContext{
+				IpContext: &networkservice.IPContext{SrcIpAddrs: srcAddrs},
+			},
+		},
+	}
+}
+
+// parseRuleSets 解析配置文件格式的规则集
+func parseRuleSets(t *testing.T, raw string) []acl.RuleSet {
+	t.Helper()
+	sets, err := acl.ParseRuleSets([]byte(raw))
+	if err != nil {
+		t.Fatalf("解析规则失败: %v", err)
+	}
+	return sets
+}
+
+// boundRules 返回接口上绑定的入站和出站 ACL 的规则，按绑定顺序
+func boundRules(t *testing.T, backend *acltest.Backend) (input, output [][]acl_types.ACLRule) {
+	t.Helper()
+	bound := backend.Interface(testSwIfIndex)
+	for _, index := range bound.Input {
+		info, ok := backend.ACL(index)
+		if !ok {
+			t.Fatalf("绑定的入站 ACL %d 不存在", index)
+		}
+		input = append(input, info.Rules)
+	}
+	for _, index := range bound.Output {
+		info, ok := backend.ACL(index)
+		if !ok {
+			t.Fatalf("绑定的出站 ACL %d 不存在", index)
+		}
+		output = append(output, info.Rules)
+	}
+	return input, output
+}
+
+// firstMatch 按 VPP 的首条匹配语义返回源地址为 src 的数据包命中的规则动作，没有命中时为隐式拒绝
+func firstMatch(t *testing.T, rules []acl_types.ACLRule, src string) acl_types.ACLAction {
+	t.Helper()
+	addr := netip.MustParseAddr(src)
+	for i := range rules {
+		prefix, err := netip.ParsePrefix(rules[i].SrcPrefix.String())
+		if err != nil {
+			t.Fatalf("无效的规则源前缀 %v: %v", rules[i].SrcPrefix, err)
+		}
+		if prefix.Contains(addr) {
+			return rules[i].IsPermit
+		}
+	}
+	return acl_types.ACL_ACTION_API_DENY
+}
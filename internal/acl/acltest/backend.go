@@ -0,0 +1,292 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package acltest 提供 acl.ACLBackend 的内存实现，用于在没有 VPP 的情况下测试 ACL 服务器
+package acltest
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/networkservicemesh/govpp/binapi/acl_types"
+	"github.com/networkservicemesh/govpp/binapi/interface_types"
+	"github.com/pkg/errors"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+)
+
+// 后端方法名，用于 Call.Method 和 InjectError
+const (
+	MethodCreate   = "Create"
+	MethodReplace  = "Replace"
+	MethodBind     = "Bind"
+	MethodBindings = "Bindings"
+	MethodDelete   = "Delete"
+	MethodDump     = "Dump"
+)
+
+// Call 一次后端调用的记录
+type Call struct {
+	Method    string
+	Index     uint32
+	SwIfIndex interface_types.InterfaceIndex
+	Tag       string
+	Rules     []acl_types.ACLRule
+	Input     []uint32
+	Output    []uint32
+	Err       error
+}
+
+// InterfaceACLs 接口上绑定的入站和出站 ACL 列表
+type InterfaceACLs struct {
+	Input  []uint32
+	Output []uint32
+}
+
+// Backend 内存中的 acl.ACLBackend 实现
+//
+// 功能说明:
+//   - 记录全部调用（含失败的调用），供测试断言调用顺序和参数
+//   - 通过 InjectError 让指定方法的下一次调用返回错误，通过 InjectACLError 只让针对指定 ACL 的调用返回错误
+//   - 维护 ACL 表和每个接口的绑定状态，与 VPP 一样拒绝引用不存在的 ACL
+type Backend struct {
+	mu         sync.Mutex
+	nextIndex  uint32
+	acls       map[uint32]acl.ACLInfo
+	interfaces map[interface_types.InterfaceIndex]InterfaceACLs
+	calls      []Call
+	errs       map[string][]error
+	aclErrs    map[aclCall][]error
+}
+
+// aclCall 针对某个 ACL 的后端方法调用，用于 InjectACLError
+type aclCall struct {
+	method string
+	index  uint32
+}
+
+// NewBackend 创建空的内存后端
+func NewBackend() *Backend {
+	return &Backend{
+		acls:       make(map[uint32]acl.ACLInfo),
+		interfaces: make(map[interface_types.InterfaceIndex]InterfaceACLs),
+		errs:       make(map[string][]error),
+		aclErrs:    make(map[aclCall][]error),
+	}
+}
+
+// InjectError 让 method 的下一次调用返回 err，多次调用按顺序排队
+func (b *Backend) InjectError(method string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.errs[method] = append(b.errs[method], err)
+}
+
+// InjectACLError 让 method 对 ACL index 的下一次调用返回 err，用于 Replace 和 Delete 等带 ACL 索引的方法；
+// 多次调用按顺序排队，对其他 ACL 的调用不受影响
+func (b *Backend) InjectACLError(method string, index uint32, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := aclCall{method: method, index: index}
+	b.aclErrs[key] = append(b.aclErrs[key], err)
+}
+
+// Calls 返回到目前为止的全部调用记录
+func (b *Backend) Calls() []Call {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Call(nil), b.calls...)
+}
+
+// ACL 返回索引为 index 的 ACL
+func (b *Backend) ACL(index uint32) (acl.ACLInfo, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	info, ok := b.acls[index]
+	return info, ok
+}
+
+// ACLs 返回全部 ACL，按索引排序
+func (b *Backend) ACLs() []acl.ACLInfo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dump()
+}
+
+// Interface 返回接口上绑定的 ACL 列表
+func (b *Backend) Interface(swIfIndex interface_types.InterfaceIndex) InterfaceACLs {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.interfaces[swIfIndex]
+}
+
+// Create 实现 acl.ACLBackend
+func (b *Backend) Create(ctx context.Context, tag string, rules []acl_types.ACLRule) (uint32, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	call := Call{Method: MethodCreate, Tag: tag, Rules: copyRules(rules)}
+	if err := b.fail(ctx, &call); err != nil {
+		return 0, err
+	}
+	call.Index = b.nextIndex
+	b.nextIndex++
+	b.acls[call.Index] = acl.ACLInfo{Index: call.Index, Tag: tag, Rules: call.Rules}
+	b.calls = append(b.calls, call)
+	return call.Index, nil
+}
+
+// Replace 实现 acl.ACLBackend
+func (b *Backend) Replace(ctx context.Context, index uint32, tag string, rules []acl_types.ACLRule) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	call := Call{Method: MethodReplace, Index: index, Tag: tag, Rules: copyRules(rules)}
+	if err := b.fail(ctx, &call); err != nil {
+		return err
+	}
+	if _, ok := b.acls[index]; !ok {
+		return b.record(call, errors.Errorf("ACL %d 不存在", index))
+	}
+	b.acls[index] = acl.ACLInfo{Index: index, Tag: tag, Rules: call.Rules}
+	b.calls = append(b.calls, call)
+	return nil
+}
+
+// Bind 实现 acl.ACLBackend
+func (b *Backend) Bind(ctx context.Context, swIfIndex interface_types.InterfaceIndex, input, output []uint32) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	call := Call{
+		Method:    MethodBind,
+		SwIfIndex: swIfIndex,
+		Input:     append([]uint32(nil), input...),
+		Output:    append([]uint32(nil), output...),
+	}
+	if err := b.fail(ctx, &call); err != nil {
+		return err
+	}
+	for _, index := range append(append([]uint32(nil), input...), output...) {
+		if _, ok := b.acls[index]; !ok {
+			return b.record(call, errors.Errorf("ACL %d 不存在", index))
+		}
+	}
+	if len(call.Input)+len(call.Output) == 0 {
+		delete(b.interfaces, swIfIndex)
+	} else {
+		b.interfaces[swIfIndex] = InterfaceACLs{Input: call.Input, Output: call.Output}
+	}
+	b.calls = append(b.calls, call)
+	return nil
+}
+
+// Bindings 实现 acl.ACLBackend
+func (b *Backend) Bindings(ctx context.Context, swIfIndex interface_types.InterfaceIndex) ([]uint32, []uint32, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	call := Call{Method: MethodBindings, SwIfIndex: swIfIndex}
+	if err := b.fail(ctx, &call); err != nil {
+		return nil, nil, err
+	}
+	bound := b.interfaces[swIfIndex]
+	call.Input = append([]uint32(nil), bound.Input...)
+	call.Output = append([]uint32(nil), bound.Output...)
+	b.calls = append(b.calls, call)
+	return call.Input, call.Output, nil
+}
+
+// Delete 实现 acl.ACLBackend
+func (b *Backend) Delete(ctx context.Context, index uint32) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	call := Call{Method: MethodDelete, Index: index}
+	if err := b.fail(ctx, &call); err != nil {
+		return err
+	}
+	if _, ok := b.acls[index]; !ok {
+		return b.record(call, errors.Errorf("ACL %d 不存在", index))
+	}
+	for swIfIndex, bound := range b.interfaces {
+		if contains(bound.Input, index) || contains(bound.Output, index) {
+			return b.record(call, errors.Errorf("ACL %d 仍绑定在接口 %d 上", index, swIfIndex))
+		}
+	}
+	delete(b.acls, index)
+	b.calls = append(b.calls, call)
+	return nil
+}
+
+// Dump 实现 acl.ACLBackend
+func (b *Backend) Dump(ctx context.Context) ([]acl.ACLInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	call := Call{Method: MethodDump}
+	if err := b.fail(ctx, &call); err != nil {
+		return nil, err
+	}
+	b.calls = append(b.calls, call)
+	return b.dump(), nil
+}
+
+// fail 返回上下文错误或注入的错误，并记录失败的调用；调用方需持有 b.mu
+func (b *Backend) fail(ctx context.Context, call *Call) error {
+	if err := ctx.Err(); err != nil {
+		return b.record(*call, err)
+	}
+	if queued := b.errs[call.Method]; len(queued) > 0 {
+		b.errs[call.Method] = queued[1:]
+		return b.record(*call, queued[0])
+	}
+	key := aclCall{method: call.Method, index: call.Index}
+	if queued := b.aclErrs[key]; len(queued) > 0 {
+		b.aclErrs[key] = queued[1:]
+		return b.record(*call, queued[0])
+	}
+	return nil
+}
+
+// record 记录一次失败的调用并返回 err；调用方需持有 b.mu
+func (b *Backend) record(call Call, err error) error {
+	call.Err = err
+	b.calls = append(b.calls, call)
+	return err
+}
+
+func (b *Backend) dump() []acl.ACLInfo {
+	rv := make([]acl.ACLInfo, 0, len(b.acls))
+	for _, info := range b.acls {
+		rv = append(rv, info)
+	}
+	sort.Slice(rv, func(i, j int) bool { return rv[i].Index < rv[j].Index })
+	return rv
+}
+
+func copyRules(rules []acl_types.ACLRule) []acl_types.ACLRule {
+	return append([]acl_types.ACLRule(nil), rules...)
+}
+
+func contains(indices []uint32, index uint32) bool {
+	for _, i := range indices {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
+
+var _ acl.ACLBackend = (*Backend)(nil)
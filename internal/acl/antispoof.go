@@ -0,0 +1,108 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"net/netip"
+
+	"github.com/networkservicemesh/govpp/binapi/acl_types"
+	"github.com/networkservicemesh/govpp/binapi/ip_types"
+	"github.com/pkg/errors"
+)
+
+// antiSpoofRuleName 自动生成的防伪造规则名称
+const antiSpoofRuleName = "anti-spoof"
+
+// antiSpoofRules 生成拒绝伪造源地址的入站规则
+//
+// 功能说明:
+//   - 对每个地址族，拒绝源地址不在 srcAddrs 中的流量：用若干前缀覆盖
+//     "整个地址空间减去 srcAddrs"，每个前缀一条拒绝规则
+//   - srcAddrs 中没有某个地址族的地址时，该地址族的全部流量都被拒绝
+//
+// 参数:
+//   - srcAddrs: 连接的 IpContext.SrcIpAddrs
+//
+// 返回:
+//   - []Rule: 拒绝规则，放在入站 ACL 的最前面
+//   - error: 地址格式错误
+func antiSpoofRules(srcAddrs []string) ([]Rule, error) {
+	var allowed []netip.Prefix
+	for _, addr := range srcAddrs {
+		p, err := netip.ParsePrefix(addr)
+		if err != nil {
+			ip, addrErr := netip.ParseAddr(addr)
+			if addrErr != nil {
+				return nil, errors.Wrapf(addrErr, "无效的源地址 %q", addr)
+			}
+			p = netip.PrefixFrom(ip, ip.BitLen())
+		}
+		allowed = append(allowed, p.Masked())
+	}
+
+	var rv []Rule
+	for _, root := range []netip.Prefix{netip.MustParsePrefix("0.0.0.0/0"), netip.MustParsePrefix("::/0")} {
+		for _, p := range complement(root, allowed) {
+			src, err := ip_types.ParsePrefix(p.String())
+			if err != nil {
+				return nil, errors.Wrapf(err, "无效的前缀 %v", p)
+			}
+			rv = append(rv, Rule{
+				Name:    antiSpoofRuleName,
+				RuleSet: antiSpoofRuleName,
+				ACLRule: acl_types.ACLRule{
+					IsPermit:  acl_types.ACL_ACTION_API_DENY,
+					SrcPrefix: src,
+					DstPrefix: anyPrefix(src.Address.Af),
+				},
+			})
+		}
+	}
+	return rv, nil
+}
+
+// complement 返回 root 中不被 allowed 覆盖的部分，表示为最少的前缀列表
+func complement(root netip.Prefix, allowed []netip.Prefix) []netip.Prefix {
+	overlaps := false
+	for _, p := range allowed {
+		if !p.Overlaps(root) {
+			continue
+		}
+		if p.Bits() <= root.Bits() {
+			return nil
+		}
+		overlaps = true
+	}
+	if !overlaps {
+		return []netip.Prefix{root}
+	}
+	lo, hi := split(root)
+	return append(complement(lo, allowed), complement(hi, allowed)...)
+}
+
+// split 将前缀平分为两个长度加一的子前缀
+func split(p netip.Prefix) (lo, hi netip.Prefix) {
+	bits := p.Bits()
+	addr := p.Addr().AsSlice()
+	lo = netip.PrefixFrom(p.Addr(), bits+1)
+	addr[bits/8] |= 0x80 >> (bits % 8)
+	hiAddr, _ := netip.AddrFromSlice(addr)
+	return lo, netip.PrefixFrom(hiAddr, bits+1)
+}
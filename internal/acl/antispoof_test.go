@@ -0,0 +1,137 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/networkservicemesh/govpp/binapi/acl_types"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+	"github.com/ifzzh/cmd-nse-template/internal/acl/acltest"
+)
+
+func TestAntiSpoofWithoutPolicyRules(t *testing.T) {
+	backend := acltest.NewBackend()
+	server := newTestServer(backend, acl.NewPolicy(nil), acl.WithAntiSpoof())
+
+	if _, err := server.Request(context.Background(), testRequest("conn-1", "10.0.0.2/32")); err != nil {
+		t.Fatalf("Request 失败: %v", err)
+	}
+
+	input, output := boundRules(t, backend)
+	if len(input) != 1 {
+		t.Fatalf("入站 ACL 数量 = %d，期望 1", len(input))
+	}
+	if len(output) != 0 {
+		t.Fatalf("出站 ACL 数量 = %d，期望 0（不绑定空的出站 ACL）", len(output))
+	}
+	for src, want := range map[string]acl_types.ACLAction{
+		"10.0.0.2":    acl_types.ACL_ACTION_API_PERMIT,
+		"10.0.0.3":    acl_types.ACL_ACTION_API_DENY,
+		"192.168.1.1": acl_types.ACL_ACTION_API_DENY,
+		"2001:db8::1": acl_types.ACL_ACTION_API_DENY,
+	} {
+		if got := firstMatch(t, input[0], src); got != want {
+			t.Errorf("源地址 %s 的动作 = %v，期望 %v", src, got, want)
+		}
+	}
+}
+
+func TestAntiSpoofWithoutSourceAddresses(t *testing.T) {
+	backend := acltest.NewBackend()
+	server := newTestServer(backend, acl.NewPolicy(nil), acl.WithAntiSpoof())
+
+	if _, err := server.Request(context.Background(), testRequest("conn-1")); err != nil {
+		t.Fatalf("Request 失败: %v", err)
+	}
+
+	if acls := backend.ACLs(); len(acls) != 0 {
+		t.Fatalf("创建了 %d 个 ACL，期望不创建（空 ACL 会拒绝全部流量）", len(acls))
+	}
+	if bound := backend.Interface(testSwIfIndex); len(bound.Input)+len(bound.Output) != 0 {
+		t.Fatalf("接口绑定了 ACL: %+v", bound)
+	}
+}
+
+func TestAntiSpoofWithPolicyRules(t *testing.T) {
+	backend := acltest.NewBackend()
+	policy := acl.NewPolicy(parseRuleSets(t, `
+allow 10.0.0.0/8:
+  srcprefix: 10.0.0.0/8
+  ispermit: 1
+`))
+	server := newTestServer(backend, policy, acl.WithAntiSpoof())
+
+	if _, err := server.Request(context.Background(), testRequest("conn-1", "10.0.0.2/32")); err != nil {
+		t.Fatalf("Request 失败: %v", err)
+	}
+
+	input, output := boundRules(t, backend)
+	if len(input) != 1 || len(output) != 1 {
+		t.Fatalf("入站/出站 ACL 数量 = %d/%d，期望 1/1", len(input), len(output))
+	}
+	// 策略有规则时不追加放行全部流量的规则，未匹配策略的流量由隐式拒绝处理
+	for src, want := range map[string]acl_types.ACLAction{
+		"10.0.0.2": acl_types.ACL_ACTION_API_PERMIT,
+		"10.0.0.3": acl_types.ACL_ACTION_API_DENY,
+	} {
+		if got := firstMatch(t, input[0], src); got != want {
+			t.Errorf("源地址 %s 的动作 = %v，期望 %v", src, got, want)
+		}
+	}
+	if last := input[0][len(input[0])-1]; last.IsPermit != acl_types.ACL_ACTION_API_PERMIT || last.SrcPrefix.Len != 8 {
+		t.Errorf("入站最后一条规则 = %+v，期望策略规则", last)
+	}
+}
+
+func TestStatefulPolicyDeniesUnsolicitedEgress(t *testing.T) {
+	backend := acltest.NewBackend()
+	policy := acl.NewPolicy(parseRuleSets(t, `
+ruleSets:
+  - name: web
+    stateful: true
+    rules:
+      allow all:
+        ispermit: 1
+`))
+	server := newTestServer(backend, policy)
+
+	if _, err := server.Request(context.Background(), testRequest("conn-1", "10.0.0.2/32")); err != nil {
+		t.Fatalf("Request 失败: %v", err)
+	}
+
+	input, output := boundRules(t, backend)
+	if len(input) != 1 || len(output) != 1 {
+		t.Fatalf("入站/出站 ACL 数量 = %d/%d，期望 1/1", len(input), len(output))
+	}
+	if input[0][0].IsPermit != acl_types.ACL_ACTION_API_PERMIT_REFLECT {
+		t.Errorf("入站规则动作 = %v，期望 permit+reflect", input[0][0].IsPermit)
+	}
+	if len(output[0]) == 0 {
+		t.Fatal("绑定了空的出站 ACL")
+	}
+	for _, rule := range output[0] {
+		if rule.IsPermit != acl_types.ACL_ACTION_API_DENY {
+			t.Errorf("出站规则 %+v，期望拒绝全部流量", rule)
+		}
+	}
+}
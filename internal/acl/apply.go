@@ -0,0 +1,322 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/govpp/binapi/acl_types"
+	"github.com/networkservicemesh/govpp/binapi/interface_types"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// connState 一个连接的 ACL 状态
+//
+// mu 保护其余字段，并串行化同一连接的 Request、Close、策略更新和降级重试。
+// degraded 为 true 表示连接按 fail-open 模式建立但 ACL 尚未下发，cancelRetry 停止后台重试。
+// quarantined 为 true 表示连接已被隔离，使用隔离规则代替策略规则（见 quarantineRules）。
+// path 为连接路径上各段的名称（第一段为 NSC），inRules/outRules 为已下发的入站和出站规则。
+type connState struct {
+	mu          sync.Mutex
+	closed      bool
+	degraded    bool
+	quarantined bool
+	cancelRetry context.CancelFunc
+	swIfIndex   interface_types.InterfaceIndex
+	service     string
+	ipContext   *networkservice.IPContext
+	path        []string
+	ingress     []uint32
+	egress      []uint32
+	inRules     []Rule
+	outRules    []Rule
+}
+
+// indices 返回连接当前的全部 ACL 索引
+func (st *connState) indices() []uint32 {
+	return append(append([]uint32{}, st.ingress...), st.egress...)
+}
+
+// hasRules 判断当前策略是否可能需要为网络服务 service 的连接下发 ACL
+//
+// 开启防伪造时总是返回 true；连接没有源地址且策略没有规则时，apply 不下发 ACL。
+func (a *aclServer) hasRules(service string) bool {
+	return a.antiSpoof || len(a.rules(service)) > 0
+}
+
+// apply 按当前策略下发连接的 ACL，调用方需持有 st.mu
+//
+// 功能说明:
+//   - 按紧急模式和隔离状态选择规则（见 activeRules）
+//   - 没有需要下发的规则时（策略没有规则，且未开启防伪造或连接没有源地址），移除连接已有的 ACL
+//     （不绑定空 ACL，空 ACL 会拒绝全部流量）
+//   - 切分后的 ACL 数量不变时，通过 ACLAddReplace 原地替换规则，索引和接口绑定不变；
+//     任一方向替换失败时恢复两个方向原有的规则（见 replaceInPlace）
+//   - 否则创建新的 ACL，在接口 ACL 列表中替换原有的 ACL 后删除原有的 ACL；
+//     失败时原有的 ACL 保持生效
+//   - 更新规则命中计数和会话数的登记
+//   - 成功后清除连接的降级标记（见 degrade）
+//
+// 参数:
+//   - ctx: 上下文
+//   - connID: 连接 ID
+//   - st: 连接 ACL 状态
+//
+// 返回:
+//   - error: 错误信息
+func (a *aclServer) apply(ctx context.Context, connID string, st *connState) error {
+	inRules, outRules, err := a.connectionRules(ctx, a.activeRules(ctx, st), st.ipContext)
+	if err != nil {
+		return err
+	}
+	if len(inRules) == 0 && len(outRules) == 0 {
+		a.release(ctx, connID, st)
+		a.recoverDegraded(ctx, connID, st)
+		return nil
+	}
+	if a.counters != nil {
+		a.counters.enable(ctx)
+	}
+	inChunks, outChunks := chunk(vppRules(inRules), a.chunkSize), chunk(vppRules(outRules), a.chunkSize)
+
+	if len(st.ingress) > 0 && len(st.ingress) == len(inChunks) && len(st.egress) == len(outChunks) {
+		// 原地替换规则，失败时恢复原有的规则
+		if err = a.replaceInPlace(ctx, connID, st, inChunks, outChunks); err != nil {
+			return err
+		}
+	} else {
+		// 创建新的 ACL 并替换接口上原有的 ACL
+		previous := st.indices()
+		ingress, egress, createErr := create(ctx, a.backend, connID, st.swIfIndex, a.position, a.chunkSize, previous, vppRules(inRules), vppRules(outRules))
+		if createErr != nil {
+			return createErr
+		}
+		a.untrack(connID, previous)
+		deleteACLs(ctx, a.backend, connID, previous)
+		st.ingress, st.egress = ingress, egress
+	}
+	st.inRules, st.outRules = inRules, outRules
+
+	// 登记 ACL，用于采集逐条规则命中计数和有状态规则的会话数
+	if a.counters != nil {
+		a.trackChunks(connID, directionIngress, st.ingress, inRules)
+		a.trackChunks(connID, directionEgress, st.egress, outRules)
+		if hasStateful(inRules) {
+			a.counters.trackSessions(connID, st.swIfIndex)
+		} else {
+			a.counters.untrackSessions(connID)
+		}
+	}
+	a.recoverDegraded(ctx, connID, st)
+	return nil
+}
+
+// replaceInPlace 通过 ACLAddReplace 原地替换连接入站和出站 ACL 的规则，调用方需持有 st.mu
+//
+// 两个方向分别替换，任一 ACL 替换失败时按连接原有的规则（st.inRules、st.outRules）恢复两个方向的 ACL，
+// 避免接口上留下新的入站规则和旧的出站规则；恢复也失败时记录错误。
+func (a *aclServer) replaceInPlace(ctx context.Context, connID string, st *connState, inChunks, outChunks [][]acl_types.ACLRule) error {
+	err := replaceACLs(ctx, a.backend, connID, st.ingress, inChunks)
+	if err == nil {
+		err = replaceACLs(ctx, a.backend, connID, st.egress, outChunks)
+	}
+	if err == nil {
+		return nil
+	}
+
+	logger := log.FromContext(ctx).WithField("acl_server", "replace").WithField("connection", connID)
+	prevIn, prevOut := chunk(vppRules(st.inRules), a.chunkSize), chunk(vppRules(st.outRules), a.chunkSize)
+	if len(prevIn) != len(st.ingress) || len(prevOut) != len(st.egress) {
+		logger.Errorf("原地替换规则失败，无法恢复原有的规则（ACL 数量不一致），连接的 ACL 可能与策略不一致: %v", err)
+		return err
+	}
+	restoreErr := replaceACLs(ctx, a.backend, connID, st.ingress, prevIn)
+	if restoreErr == nil {
+		restoreErr = replaceACLs(ctx, a.backend, connID, st.egress, prevOut)
+	}
+	if restoreErr != nil {
+		logger.Errorf("原地替换规则失败后恢复原有的规则也失败，连接的 ACL 可能与策略不一致: %v", restoreErr)
+	}
+	return err
+}
+
+// release 从接口上移除连接的 ACL 并删除，调用方需持有 st.mu
+//
+// 失败只记录日志：VPP 不允许删除仍绑定在接口上的 ACL，因此先解绑再删除。
+func (a *aclServer) release(ctx context.Context, connID string, st *connState) {
+	indices := st.indices()
+	if len(indices) == 0 {
+		return
+	}
+	a.untrack(connID, indices)
+	if err := unbind(ctx, a.backend, connID, st.swIfIndex, indices); err != nil {
+		log.FromContext(ctx).WithField("acl_server", "release").Debugf("ACL 服务器: 解绑 ACL 失败: %v", err)
+	}
+	deleteACLs(ctx, a.backend, connID, indices)
+	st.ingress, st.egress = nil, nil
+	st.inRules, st.outRules = nil, nil
+}
+
+// reapply 策略变化后重新下发全部已有连接的 ACL
+//
+// 单个连接更新失败时记录错误，该连接原有的 ACL 保持生效，不影响其他连接。
+func (a *aclServer) reapply(ctx context.Context) {
+	a.applyAll(ctx)
+}
+
+// applyAll 重新下发全部已有连接的 ACL，返回连接数和下发失败的连接数
+func (a *aclServer) applyAll(ctx context.Context) (total, failed int) {
+	defer a.reconciles.begin()()
+	logger := log.FromContext(ctx).WithField("acl_server", "reapply")
+	if a.counters != nil {
+		a.counters.declare(a.declaredRules())
+	}
+	a.checkServiceRuleSets(ctx)
+	a.conns.Range(func(connID string, st *connState) bool {
+		st.mu.Lock()
+		defer st.mu.Unlock()
+		if st.closed {
+			return true
+		}
+		total++
+		err := a.retryPolicy.retry(ctx, connID, func(ctx context.Context) error {
+			return a.apply(ctx, connID, st)
+		})
+		if err != nil {
+			failed++
+			logger.WithField("connection", connID).Errorf("策略更新后下发 ACL 失败，保留原有 ACL: %v", err)
+		}
+		return true
+	})
+	return total, failed
+}
+
+// reconciles 记录进行中的全部连接 ACL 重新下发（见 applyAll）的开始时间，用于检测策略同步停滞
+//
+// 策略变化和紧急模式切换可能同时触发重新下发，每次分别记录。
+type reconciles struct {
+	mu      sync.Mutex
+	next    uint64
+	running map[uint64]time.Time
+}
+
+// begin 记录一次重新下发开始，返回的函数在下发结束时调用
+func (r *reconciles) begin() (end func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running == nil {
+		r.running = make(map[uint64]time.Time)
+	}
+	id := r.next
+	r.next++
+	r.running[id] = time.Now()
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.running, id)
+	}
+}
+
+// oldest 返回进行中最早开始的一次重新下发已持续的时间，没有进行中的重新下发时返回 0
+func (r *reconciles) oldest() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rv time.Duration
+	for _, start := range r.running {
+		if d := time.Since(start); d > rv {
+			rv = d
+		}
+	}
+	return rv
+}
+
+// untrack 注销连接 ACL 的计数采集
+func (a *aclServer) untrack(connID string, indices []uint32) {
+	if a.counters == nil {
+		return
+	}
+	for _, index := range indices {
+		a.counters.untrack(index)
+	}
+	a.counters.untrackSessions(connID)
+}
+
+// trackChunks 登记一个方向上按 chunkSize 切分的各个 ACL
+func (a *aclServer) trackChunks(connID, direction string, indices []uint32, rules []Rule) {
+	chunks := chunk(rules, a.chunkSize)
+	offset := 0
+	for i, index := range indices {
+		if i >= len(chunks) {
+			break
+		}
+		a.counters.track(connID, direction, index, offset, chunks[i])
+		offset += len(chunks[i])
+	}
+}
+
+// connectionRules 生成连接的入站和出站规则
+//
+// 功能说明:
+//   - 用连接 IpContext 中的地址替换规则模板（见 resolveRules）
+//   - 开启防伪造时，在入站规则最前面加入拒绝伪造源地址的规则；本元素是服务端元素，
+//     入站方向即 NSC 侧接口的输入方向。策略没有规则时，防伪造规则之后加入放行全部流量的规则，
+//     且不下发出站 ACL，只拒绝伪造源地址的流量
+//   - 策略的规则全部是有状态规则时，出站方向加入拒绝全部流量的规则，与规则的语义一致：
+//     只放行入站 ACL 建立的会话的回程流量（不绑定空的出站 ACL）
+//
+// 参数:
+//   - ctx: 上下文
+//   - rules: 策略中的规则列表
+//   - ipContext: 连接的 IP 上下文
+//
+// 返回:
+//   - ingress: 入站规则列表
+//   - egress: 出站规则列表
+//   - err: 错误信息
+func (a *aclServer) connectionRules(ctx context.Context, rules []Rule, ipContext *networkservice.IPContext) (ingress, egress []Rule, err error) {
+	resolved, err := resolveRules(rules, ipContext)
+	if err != nil {
+		return nil, nil, err
+	}
+	ingress, egress = ingressRules(resolved), egressRules(resolved)
+	if len(resolved) > 0 && len(egress) == 0 {
+		egress = matchAllRules(statefulRuleName, acl_types.ACL_ACTION_API_DENY)
+	}
+
+	if a.antiSpoof {
+		if len(ipContext.GetSrcIpAddrs()) == 0 {
+			log.FromContext(ctx).WithField("acl_server", "anti-spoof").Debug("连接没有源地址，跳过防伪造规则")
+			return ingress, egress, nil
+		}
+		var spoofRules []Rule
+		if spoofRules, err = antiSpoofRules(ipContext.GetSrcIpAddrs()); err != nil {
+			return nil, nil, err
+		}
+		if len(resolved) == 0 {
+			ingress = matchAllRules(antiSpoofRuleName, acl_types.ACL_ACTION_API_PERMIT)
+		}
+		ingress = append(spoofRules, ingress...)
+	}
+	return ingress, egress, nil
+}
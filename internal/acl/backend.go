@@ -0,0 +1,148 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+	"io"
+
+	"github.com/networkservicemesh/govpp/binapi/acl"
+	"github.com/networkservicemesh/govpp/binapi/acl_types"
+	"github.com/networkservicemesh/govpp/binapi/interface_types"
+	"github.com/pkg/errors"
+	"go.fd.io/govpp/api"
+)
+
+// ACLInfo 后端中一个 ACL 的内容
+type ACLInfo struct {
+	Index uint32
+	Tag   string
+	Rules []acl_types.ACLRule
+}
+
+// ACLBackend ACL 编程后端
+//
+// ACL 服务器只通过该接口编程 ACL，默认实现直接调用 VPP ACL 插件的二进制 API；
+// 测试中可以替换为 acltest 包提供的内存实现，在没有 VPP 的情况下验证完整的
+// Request/Close 生命周期。
+type ACLBackend interface {
+	// Create 创建新的 ACL，返回 ACL 索引
+	Create(ctx context.Context, tag string, rules []acl_types.ACLRule) (uint32, error)
+	// Replace 用 rules 替换已有 ACL 的全部规则，ACL 索引保持不变
+	Replace(ctx context.Context, index uint32, tag string, rules []acl_types.ACLRule) error
+	// Bind 设置接口的入站和出站 ACL 列表，替换接口上原有的列表
+	Bind(ctx context.Context, swIfIndex interface_types.InterfaceIndex, input, output []uint32) error
+	// Bindings 返回接口上当前的入站和出站 ACL 列表
+	Bindings(ctx context.Context, swIfIndex interface_types.InterfaceIndex) (input, output []uint32, err error)
+	// Delete 删除 ACL
+	Delete(ctx context.Context, index uint32) error
+	// Dump 返回后端中的全部 ACL
+	Dump(ctx context.Context) ([]ACLInfo, error)
+}
+
+// vppBackend 基于 VPP ACL 插件二进制 API 的 ACLBackend 实现
+type vppBackend struct {
+	vppConn api.Connection
+}
+
+// NewVPPBackend 创建基于 VPP ACL 插件二进制 API 的 ACLBackend
+func NewVPPBackend(vppConn api.Connection) ACLBackend {
+	return &vppBackend{vppConn: vppConn}
+}
+
+func (b *vppBackend) Create(ctx context.Context, tag string, rules []acl_types.ACLRule) (uint32, error) {
+	rsp, err := acl.NewServiceClient(b.vppConn).ACLAddReplace(ctx, &acl.ACLAddReplace{
+		ACLIndex: ^uint32(0), // 0xFFFFFFFF 表示创建新 ACL
+		Tag:      tag,
+		Count:    uint32(len(rules)),
+		R:        rules,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rsp.ACLIndex, nil
+}
+
+func (b *vppBackend) Replace(ctx context.Context, index uint32, tag string, rules []acl_types.ACLRule) error {
+	_, err := acl.NewServiceClient(b.vppConn).ACLAddReplace(ctx, &acl.ACLAddReplace{
+		ACLIndex: index,
+		Tag:      tag,
+		Count:    uint32(len(rules)),
+		R:        rules,
+	})
+	return err
+}
+
+func (b *vppBackend) Bind(ctx context.Context, swIfIndex interface_types.InterfaceIndex, input, output []uint32) error {
+	acls := make([]uint32, 0, len(input)+len(output))
+	acls = append(acls, input...)
+	acls = append(acls, output...)
+	_, err := acl.NewServiceClient(b.vppConn).ACLInterfaceSetACLList(ctx, &acl.ACLInterfaceSetACLList{
+		SwIfIndex: swIfIndex,
+		Count:     uint8(len(acls)),
+		NInput:    uint8(len(input)),
+		Acls:      acls,
+	})
+	return err
+}
+
+func (b *vppBackend) Bindings(ctx context.Context, swIfIndex interface_types.InterfaceIndex) (input, output []uint32, err error) {
+	stream, err := acl.NewServiceClient(b.vppConn).ACLInterfaceListDump(ctx, &acl.ACLInterfaceListDump{SwIfIndex: swIfIndex})
+	if err != nil {
+		return nil, nil, err
+	}
+	for {
+		details, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			return input, output, nil
+		}
+		if recvErr != nil {
+			return nil, nil, recvErr
+		}
+		if details.SwIfIndex != swIfIndex || int(details.NInput) > len(details.Acls) {
+			continue
+		}
+		input = append(input, details.Acls[:details.NInput]...)
+		output = append(output, details.Acls[details.NInput:]...)
+	}
+}
+
+func (b *vppBackend) Delete(ctx context.Context, index uint32) error {
+	_, err := acl.NewServiceClient(b.vppConn).ACLDel(ctx, &acl.ACLDel{ACLIndex: index})
+	return err
+}
+
+func (b *vppBackend) Dump(ctx context.Context) ([]ACLInfo, error) {
+	stream, err := acl.NewServiceClient(b.vppConn).ACLDump(ctx, &acl.ACLDump{ACLIndex: ^uint32(0)})
+	if err != nil {
+		return nil, err
+	}
+	var rv []ACLInfo
+	for {
+		details, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			return rv, nil
+		}
+		if recvErr != nil {
+			return nil, recvErr
+		}
+		rv = append(rv, ACLInfo{Index: details.ACLIndex, Tag: details.Tag, Rules: details.R})
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/govpp/binapi/interface_types"
+	"github.com/pkg/errors"
+)
+
+// Position 本元素的 ACL 在接口 ACL 列表中的位置
+type Position string
+
+const (
+	// PositionPrepend 插入到其他组件 ACL 之前，优先匹配本元素的规则
+	PositionPrepend Position = "prepend"
+	// PositionAppend 追加到其他组件 ACL 之后
+	PositionAppend Position = "append"
+)
+
+// Decode 解析环境变量中的位置配置（实现 envconfig.Decoder）
+func (p *Position) Decode(value string) error {
+	switch Position(value) {
+	case "":
+		*p = PositionPrepend
+	case PositionPrepend, PositionAppend:
+		*p = Position(value)
+	default:
+		return errors.Errorf("未知的 ACL 位置 %q，可选值: %s, %s", value, PositionPrepend, PositionAppend)
+	}
+	return nil
+}
+
+// insert 将 own 按位置合并到 existing 中，existing 中已有的 own 索引先被移除
+func (p Position) insert(existing, own []uint32) []uint32 {
+	others := without(existing, own)
+	rv := make([]uint32, 0, len(others)+len(own))
+	if p == PositionAppend {
+		rv = append(rv, others...)
+		return append(rv, own...)
+	}
+	rv = append(rv, own...)
+	return append(rv, others...)
+}
+
+// bind 将本元素的 ACL 绑定到接口，保留其他组件已绑定的 ACL
+//
+// 功能说明:
+//  1. 通过 ACLInterfaceListDump 读取接口上现有的入站/出站 ACL 列表
+//  2. 移除 previous，按 position 把 ingress/egress 插入对应列表
+//  3. 检查合计不超过每个接口 255 个 ACL 的上限
+//  4. 通过 ACLInterfaceSetACLList 写回完整列表
+//
+// 参数:
+//   - ctx: 上下文
+//   - backend: ACL 编程后端
+//   - connID: 连接 ID
+//   - swIfIndex: 软件接口索引
+//   - position: 本元素 ACL 的插入位置
+//   - previous: 要替换掉的本元素原有 ACL 索引
+//   - ingress: 本元素的入站 ACL 索引
+//   - egress: 本元素的出站 ACL 索引
+//
+// 返回:
+//   - error: 错误信息
+func bind(ctx context.Context, backend ACLBackend, connID string, swIfIndex interface_types.InterfaceIndex, position Position, previous, ingress, egress []uint32) error {
+	input, output, err := bindings(ctx, backend, connID, swIfIndex)
+	if err != nil {
+		return err
+	}
+	input, output = position.insert(without(input, previous), ingress), position.insert(without(output, previous), egress)
+	if n := len(input) + len(output); n > maxInterfaceACLs {
+		return errors.Errorf("接口 %d 需要绑定 %d 个 ACL（本元素 %d 个），超过每个接口 %d 个 ACL 的上限",
+			swIfIndex, n, len(ingress)+len(egress), maxInterfaceACLs)
+	}
+	err = callVPP(ctx, connID, "ACLInterfaceSetACLList", func(ctx context.Context) error {
+		return backend.Bind(ctx, swIfIndex, input, output)
+	})
+	return errors.Wrap(err, "VPP API ACLInterfaceSetACLList 调用失败")
+}
+
+// unbind 从接口的 ACL 列表中移除本元素的 ACL，其他组件的 ACL 保持原有顺序
+//
+// 参数:
+//   - ctx: 上下文
+//   - backend: ACL 编程后端
+//   - connID: 连接 ID
+//   - swIfIndex: 软件接口索引
+//   - indices: 本元素的 ACL 索引
+//
+// 返回:
+//   - error: 错误信息
+func unbind(ctx context.Context, backend ACLBackend, connID string, swIfIndex interface_types.InterfaceIndex, indices []uint32) error {
+	input, output, err := bindings(ctx, backend, connID, swIfIndex)
+	if err != nil {
+		return err
+	}
+	keptInput, keptOutput := without(input, indices), without(output, indices)
+	if len(keptInput) == len(input) && len(keptOutput) == len(output) {
+		return nil
+	}
+	err = callVPP(ctx, connID, "ACLInterfaceSetACLList", func(ctx context.Context) error {
+		return backend.Bind(ctx, swIfIndex, keptInput, keptOutput)
+	})
+	return errors.Wrap(err, "VPP API ACLInterfaceSetACLList 调用失败")
+}
+
+// bindings 读取接口上现有的入站和出站 ACL 列表
+func bindings(ctx context.Context, backend ACLBackend, connID string, swIfIndex interface_types.InterfaceIndex) (input, output []uint32, err error) {
+	err = callVPP(ctx, connID, "ACLInterfaceListDump", func(ctx context.Context) error {
+		var dumpErr error
+		input, output, dumpErr = backend.Bindings(ctx, swIfIndex)
+		return dumpErr
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "VPP API ACLInterfaceListDump 调用失败")
+	}
+	return input, output, nil
+}
+
+// without 返回 indices 中不属于 remove 的索引，保持原有顺序
+func without(indices, remove []uint32) []uint32 {
+	rv := make([]uint32, 0, len(indices))
+	for _, index := range indices {
+		removed := false
+		for _, r := range remove {
+			if index == r {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			rv = append(rv, index)
+		}
+	}
+	return rv
+}
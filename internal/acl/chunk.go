@@ -0,0 +1,77 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"github.com/pkg/errors"
+)
+
+// maxInterfaceACLs 一个接口上最多可绑定的 ACL 数量
+//
+// ACLInterfaceSetACLList 的 Count 和 NInput 字段为 uint8，入站和出站 ACL 合计不能超过 255 个。
+const maxInterfaceACLs = 255
+
+// chunk 将列表按 size 切分为若干段，顺序保持不变
+//
+// size 小于等于 0 时不切分；列表为空时不返回任何段，不创建 ACL（空 ACL 会拒绝全部流量）。
+func chunk[T any](items []T, size int) [][]T {
+	if len(items) == 0 {
+		return nil
+	}
+	if size <= 0 || len(items) <= size {
+		return [][]T{items}
+	}
+	rv := make([][]T, 0, (len(items)+size-1)/size)
+	for len(items) > size {
+		rv = append(rv, items[:size:size])
+		items = items[size:]
+	}
+	return append(rv, items)
+}
+
+// chunkCount 返回 n 条规则按 size 切分后的 ACL 数量
+func chunkCount(n, size int) int {
+	if n == 0 {
+		return 0
+	}
+	if size <= 0 || n <= size {
+		return 1
+	}
+	return (n + size - 1) / size
+}
+
+// checkACLLimit 检查切分后的 ACL 数量是否超过接口上限
+//
+// 参数:
+//   - ingressRules: 入站规则数
+//   - egressRules: 出站规则数
+//   - chunkSize: 每个 ACL 的规则数上限
+//   - others: 接口上其他组件已绑定的 ACL 数量
+//
+// 返回:
+//   - error: 超过上限时返回错误，说明需要的 ACL 数量
+func checkACLLimit(ingressRules, egressRules, chunkSize, others int) error {
+	n := chunkCount(ingressRules, chunkSize) + chunkCount(egressRules, chunkSize)
+	if n+others <= maxInterfaceACLs {
+		return nil
+	}
+	return errors.Errorf("规则需要 %d 个 ACL（入站 %d 条、出站 %d 条规则，每个 ACL 最多 %d 条），加上接口上其他组件的 %d 个 ACL，超过每个接口 %d 个 ACL 的上限，请增大 NSM_ACL_CHUNK_SIZE 或减少规则",
+		n, ingressRules, egressRules, chunkSize, others, maxInterfaceACLs)
+}
@@ -25,14 +25,11 @@ package acl
 
 import (
 	"context"
-	"time"
+	"fmt"
 
-	"github.com/networkservicemesh/govpp/binapi/acl"
 	"github.com/networkservicemesh/govpp/binapi/acl_types"
+	"github.com/networkservicemesh/govpp/binapi/interface_types"
 	"github.com/pkg/errors"
-	"go.fd.io/govpp/api"
-
-	"github.com/networkservicemesh/sdk-vpp/pkg/tools/ifindex"
 
 	"github.com/networkservicemesh/sdk/pkg/tools/log"
 )
@@ -45,140 +42,142 @@ const (
 // create 在 VPP 接口上创建并应用 ACL 规则
 //
 // 功能说明:
-//   1. 获取软件接口索引 (swIfIndex)
-//   2. 创建入站 (ingress) ACL 规则
-//   3. 创建出站 (egress) ACL 规则
-//   4. 将 ACL 规则列表应用到 VPP 接口
+//   1. 创建入站 (ingress) ACL 规则（规则较多时按 chunkSize 切分为多个 ACL）
+//   2. 创建出站 (egress) ACL 规则（同上）
+//   3. 将 ACL 规则按 position 插入 VPP 接口现有的 ACL 列表（保留其他组件的 ACL），
+//      同时从列表中移除 previous（连接原有的 ACL，由调用方在成功后删除）
+//   失败时删除本次已创建的 ACL，接口 ACL 列表保持不变，保证可以安全重试
 //
 // 参数:
 //   - ctx: 上下文
-//   - vppConn: VPP API 连接
-//   - tag: ACL 标签（用于标识）
-//   - isClient: 是否为客户端模式
-//   - aRules: ACL 规则列表
+//   - backend: ACL 编程后端
+//   - connID: 连接 ID（用于生成 ACL 标签和追踪信息）
+//   - swIfIndex: 软件接口索引
+//   - position: 本元素 ACL 在接口 ACL 列表中的位置
+//   - chunkSize: 每个 ACL 的规则数上限，小于等于 0 表示不切分
+//   - previous: 要从接口上替换掉的本元素原有 ACL
+//   - ingressRules: 入站 ACL 规则列表（见 ingressRules）
+//   - egressRules: 出站 ACL 规则列表（见 egressRules）
 //
 // 返回:
-//   - []uint32: 创建的 ACL 索引列表
-//   - error: 错误信息
-func create(ctx context.Context, vppConn api.Connection, tag string, isClient bool, aRules []acl_types.ACLRule) ([]uint32, error) {
+//   - ingress: 创建的入站 ACL 索引列表
+//   - egress: 创建的出站 ACL 索引列表
+//   - err: 错误信息
+func create(ctx context.Context, backend ACLBackend, connID string, swIfIndex interface_types.InterfaceIndex, position Position, chunkSize int,
+	previous []uint32, ingressRules, egressRules []acl_types.ACLRule) (ingress, egress []uint32, err error) {
 	logger := log.FromContext(ctx).WithField("acl_server", "create")
-
-	// 获取软件接口索引
-	swIfIndex, ok := ifindex.Load(ctx, isClient)
-	if !ok {
-		return nil, errors.New("未找到软件接口索引 (swIfIndex)")
-	}
+	tag := fmt.Sprintf("%s-%s", aclTag, connID)
 	logger.Debugf("软件接口索引 swIfIndex=%v", swIfIndex)
 
-	// 构造 ACL 接口列表
-	interfaceACLList := &acl.ACLInterfaceSetACLList{
-		SwIfIndex: swIfIndex,
+	// 切分后的 ACL 数量超过接口上限时不再创建
+	if err = checkACLLimit(len(ingressRules), len(egressRules), chunkSize, 0); err != nil {
+		return nil, nil, err
 	}
 
 	// 添加入站 (ingress) ACL 规则
-	var err error
-	interfaceACLList.Acls, err = addACLToACLList(ctx, vppConn, tag, false, aRules)
+	ingress, err = addACLToACLList(ctx, backend, connID, tag, chunk(ingressRules, chunkSize))
 	if err != nil {
 		logger.Debug("添加入站 ACL 规则到列表失败")
-		return nil, err
+		return nil, nil, err
 	}
-	interfaceACLList.NInput = uint8(len(interfaceACLList.Acls))
 
 	// 添加出站 (egress) ACL 规则
-	egressACLIndeces, err := addACLToACLList(ctx, vppConn, tag, true, aRules)
+	egress, err = addACLToACLList(ctx, backend, connID, tag, chunk(egressRules, chunkSize))
 	if err != nil {
 		logger.Debug("添加出站 ACL 规则到列表失败")
-		return nil, err
+		deleteACLs(ctx, backend, connID, ingress)
+		return nil, nil, err
 	}
-	interfaceACLList.Acls = append(interfaceACLList.Acls, egressACLIndeces...)
-	interfaceACLList.Count = uint8(len(interfaceACLList.Acls))
 
-	// 将 ACL 列表应用到 VPP 接口
-	_, err = acl.NewServiceClient(vppConn).ACLInterfaceSetACLList(ctx, interfaceACLList)
-	if err != nil {
-		return nil, errors.Wrap(err, "VPP API ACLInterfaceSetACLList 调用失败")
+	// 将 ACL 插入 VPP 接口的 ACL 列表
+	if err = bind(ctx, backend, connID, swIfIndex, position, previous, ingress, egress); err != nil {
+		deleteACLs(ctx, backend, connID, append(append([]uint32{}, ingress...), egress...))
+		return nil, nil, err
 	}
-	return interfaceACLList.Acls, nil
+	return ingress, egress, nil
 }
 
-// addACLToACLList 添加 ACL 规则到 ACL 列表
-//
-// 功能说明:
-//   - 调用 VPP API ACLAddReplace 创建 ACL 规则
-//   - 记录操作耗时和索引
+// replaceACLs 原地替换已有 ACL 的规则，ACL 索引和接口绑定保持不变
 //
 // 参数:
 //   - ctx: 上下文
-//   - vppConn: VPP API 连接
-//   - tag: ACL 标签
-//   - egress: true 表示出站规则，false 表示入站规则
-//   - aRules: ACL 规则列表
+//   - backend: ACL 编程后端
+//   - connID: 连接 ID
+//   - indices: ACL 索引列表，与 chunks 一一对应
+//   - chunks: 按顺序切分的 ACL 规则列表
 //
 // 返回:
-//   - []uint32: ACL 索引列表
 //   - error: 错误信息
-func addACLToACLList(ctx context.Context, vppConn api.Connection, tag string, egress bool, aRules []acl_types.ACLRule) ([]uint32, error) {
-	var ACLIndeces []uint32
-
-	now := time.Now()
-	rsp, err := acl.NewServiceClient(vppConn).ACLAddReplace(ctx, aclAdd(tag, egress, aRules))
-	if err != nil {
-		return nil, errors.Wrap(err, "VPP API ACLAddReplace 调用失败")
+func replaceACLs(ctx context.Context, backend ACLBackend, connID string, indices []uint32, chunks [][]acl_types.ACLRule) error {
+	tag := fmt.Sprintf("%s-%s", aclTag, connID)
+	for i, index := range indices {
+		err := callVPP(ctx, connID, "ACLAddReplace", func(ctx context.Context) error {
+			return backend.Replace(ctx, index, tag, chunks[i])
+		})
+		if err != nil {
+			return errors.Wrap(err, "VPP API ACLAddReplace 调用失败")
+		}
 	}
-	log.FromContext(ctx).
-		WithField("aclIndices", rsp.ACLIndex).
-		WithField("duration", time.Since(now)).
-		WithField("vppapi", "ACLAddReplace").Debug("ACL 规则创建完成")
-	ACLIndeces = append([]uint32{rsp.ACLIndex}, ACLIndeces...)
+	return nil
+}
 
-	return ACLIndeces, nil
+// deleteACLs 删除 VPP 中的 ACL
+//
+// 删除失败只记录调试日志，不中断调用方的关闭或回滚流程。
+//
+// 参数:
+//   - ctx: 上下文
+//   - backend: ACL 编程后端
+//   - connID: 连接 ID
+//   - indices: 要删除的 ACL 索引列表
+func deleteACLs(ctx context.Context, backend ACLBackend, connID string, indices []uint32) {
+	for _, index := range indices {
+		err := callVPP(ctx, connID, "ACLDel", func(ctx context.Context) error {
+			return backend.Delete(ctx, index)
+		})
+		if err != nil {
+			log.FromContext(ctx).WithField("aclIndex", index).Debug("ACL 服务器: 删除 ACL 规则失败")
+		}
+	}
 }
 
-// aclAdd 构造 ACL 添加/替换请求
+// addACLToACLList 添加 ACL 规则到 ACL 列表
 //
 // 功能说明:
-//   - 复制 ACL 规则列表，避免修改原始数据
-//   - 对于出站规则，交换源/目标 IP 和端口，实现双向过滤
-//
-// 技术细节:
-//   VPP ACL 规则默认是入站方向，出站规则需要反转匹配条件：
-//   - 交换源/目标 IP 前缀 (SrcPrefix ↔ DstPrefix)
-//   - 交换源/目标端口 (SrcportOrIcmptypeFirst ↔ DstportOrIcmpcodeFirst)
-//   - 交换源/目标端口范围 (SrcportOrIcmptypeLast ↔ DstportOrIcmpcodeLast)
+//   - 每段规则调用一次 VPP API ACLAddReplace 创建一个 ACL，索引按段的顺序返回
+//   - 记录操作耗时、结果和索引（见 callVPP）
+//   - 中途失败时删除本次已创建的 ACL
 //
 // 参数:
+//   - ctx: 上下文
+//   - backend: ACL 编程后端
+//   - connID: 连接 ID
 //   - tag: ACL 标签
-//   - egress: true 表示出站规则，false 表示入站规则
-//   - aRules: ACL 规则列表
+//   - chunks: 按顺序切分的 ACL 规则列表（见 chunk）
 //
 // 返回:
-//   - *acl.ACLAddReplace: ACL 添加/替换请求
-func aclAdd(tag string, egress bool, aRules []acl_types.ACLRule) *acl.ACLAddReplace {
-	// 复制规则列表，避免修改原始数据
-	aRulesCopy := make([]acl_types.ACLRule, len(aRules))
-	copy(aRulesCopy, aRules)
-
-	aclAddReplace := &acl.ACLAddReplace{
-		ACLIndex: ^uint32(0), // 0xFFFFFFFF 表示创建新 ACL
-		Tag:      tag,
-		Count:    uint32(len(aRulesCopy)),
-		R:        aRulesCopy,
-	}
-
-	// 对于出站规则，交换源/目标信息
-	if egress {
-		for i := range aclAddReplace.R {
-			// 交换源/目标 IP 前缀
-			aclAddReplace.R[i].SrcPrefix, aclAddReplace.R[i].DstPrefix =
-				aclAddReplace.R[i].DstPrefix, aclAddReplace.R[i].SrcPrefix
-
-			// 交换源/目标端口（或 ICMP 类型/代码）
-			aclAddReplace.R[i].SrcportOrIcmptypeFirst, aclAddReplace.R[i].DstportOrIcmpcodeFirst =
-				aclAddReplace.R[i].DstportOrIcmpcodeFirst, aclAddReplace.R[i].SrcportOrIcmptypeFirst
-
-			aclAddReplace.R[i].SrcportOrIcmptypeLast, aclAddReplace.R[i].DstportOrIcmpcodeLast =
-				aclAddReplace.R[i].DstportOrIcmpcodeLast, aclAddReplace.R[i].SrcportOrIcmptypeLast
+//   - []uint32: ACL 索引列表
+//   - error: 错误信息
+func addACLToACLList(ctx context.Context, backend ACLBackend, connID, tag string, chunks [][]acl_types.ACLRule) ([]uint32, error) {
+	ACLIndeces := make([]uint32, 0, len(chunks))
+
+	for _, aRules := range chunks {
+		var index uint32
+		err := callVPP(ctx, connID, "ACLAddReplace", func(ctx context.Context) error {
+			var addErr error
+			index, addErr = backend.Create(ctx, tag, aRules)
+			return addErr
+		})
+		if err != nil {
+			deleteACLs(ctx, backend, connID, ACLIndeces)
+			return nil, errors.Wrap(err, "VPP API ACLAddReplace 调用失败")
 		}
+		log.FromContext(ctx).
+			WithField("aclIndices", index).
+			WithField("rules", len(aRules)).
+			WithField("vppapi", "ACLAddReplace").Debug("ACL 规则创建完成")
+		ACLIndeces = append(ACLIndeces, index)
 	}
-	return aclAddReplace
+
+	return ACLIndeces, nil
 }
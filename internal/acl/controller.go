@@ -0,0 +1,670 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotFound 规则集或规则不存在
+	ErrNotFound = errors.New("不存在")
+	// ErrInvalidRule 运行时修改的规则或位置无效
+	ErrInvalidRule = errors.New("规则无效")
+	// ErrReadOnly 规则只能通过配置文件修改（见 WithReadOnlyRules）
+	ErrReadOnly = errors.New("规则只读")
+)
+
+// ConnectionInfo 一个连接的 ACL 状态
+//
+// 字段说明:
+//   - ID: 连接 ID
+//   - NSCName: 发起连接的 NSC 名称（连接路径的第一段）
+//   - NetworkService: 连接请求的网络服务，决定下发的规则集（见 WithServiceRuleSets）
+//   - Path: 连接路径上各段的名称
+//   - SwIfIndex: 应用 ACL 的 VPP 软件接口索引
+//   - RuleSets: 已下发规则所属的规则集，按规则顺序去重
+//   - Ingress/Egress: 本元素在接口上的入站和出站 ACL 索引
+//   - Degraded: 是否为 ACL 尚未下发的 fail-open 降级连接
+//   - Quarantined: 是否已被隔离（见 Controller.Quarantine）
+type ConnectionInfo struct {
+	ID             string   `json:"id"`
+	NSCName        string   `json:"nscName"`
+	NetworkService string   `json:"networkService"`
+	Path           []string `json:"path"`
+	SwIfIndex      uint32   `json:"swIfIndex"`
+	RuleSets       []string `json:"ruleSets"`
+	Ingress        []uint32 `json:"ingress"`
+	Egress         []uint32 `json:"egress"`
+	Degraded       bool     `json:"degraded"`
+	Quarantined    bool     `json:"quarantined"`
+}
+
+// RuleInfo 一条已编译规则的可读形式
+type RuleInfo struct {
+	Position      int    `json:"position"`
+	Name          string `json:"name"`
+	RuleSet       string `json:"ruleSet"`
+	Action        string `json:"action"`
+	Proto         uint8  `json:"proto"`
+	Src           string `json:"src"`
+	Dst           string `json:"dst"`
+	SrcPorts      string `json:"srcPorts"`
+	DstPorts      string `json:"dstPorts"`
+	TCPFlagsMask  uint8  `json:"tcpFlagsMask"`
+	TCPFlagsValue uint8  `json:"tcpFlagsValue"`
+}
+
+// RuleSetInfo 一个配置的规则集及其规则（模板未替换），Temporary 为属于该规则集的临时规则
+type RuleSetInfo struct {
+	Name        string              `json:"name"`
+	Stateful    bool                `json:"stateful"`
+	FailureMode FailureMode         `json:"failureMode"`
+	Rules       []RuleInfo          `json:"rules"`
+	Temporary   []TemporaryRuleInfo `json:"temporary"`
+}
+
+// TemporaryRuleInfo 一条临时规则及其剩余有效期
+type TemporaryRuleInfo struct {
+	RuleInfo
+	ExpiresAt time.Time `json:"expiresAt"`
+	Remaining string    `json:"remaining"`
+}
+
+// ConnectionRules 一个连接已下发的入站和出站规则（模板已替换，出站规则已镜像）
+type ConnectionRules struct {
+	ID      string     `json:"id"`
+	Ingress []RuleInfo `json:"ingress"`
+	Egress  []RuleInfo `json:"egress"`
+}
+
+// Controller 从进程外查询 ACL 服务器状态、在运行时修改规则的入口
+//
+// 通过 WithController 绑定到 ACL 服务器；未绑定时查询结果为空，修改规则返回错误。
+// 配置了状态文件时（见 WithStateFile），运行时修改的规则集写入状态文件，重启后由
+// LoadState 恢复。
+type Controller struct {
+	server       *aclServer
+	statePath    string
+	configDigest string
+	readOnly     string
+}
+
+// ControllerOption ACL 控制器的可选配置项
+type ControllerOption func(c *Controller)
+
+// WithStateFile 设置保存运行时修改的状态文件，digest 为当前配置文件内容的摘要（见 ConfigDigest）
+func WithStateFile(path, digest string) ControllerOption {
+	return func(c *Controller) {
+		c.statePath, c.configDigest = path, digest
+	}
+}
+
+// WithReadOnlyRules 禁止在运行时修改规则，reason 为拒绝修改时返回的原因
+//
+// 配置了策略签名时规则只能来自签名校验通过的配置文件，启动时不加载状态文件，
+// 运行时的修改在重启后会丢失，因此直接拒绝（ErrReadOnly）。重新加载配置文件不受影响。
+func WithReadOnlyRules(reason string) ControllerOption {
+	return func(c *Controller) {
+		c.readOnly = reason
+	}
+}
+
+// NewController 创建 ACL 控制器
+func NewController(options ...ControllerOption) *Controller {
+	c := &Controller{}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// WithController 将控制器绑定到 ACL 服务器
+func WithController(c *Controller) Option {
+	return func(a *aclServer) {
+		c.server = a
+	}
+}
+
+// Connections 返回全部连接的 ACL 状态，按连接 ID 排序
+func (c *Controller) Connections() []ConnectionInfo {
+	rv := []ConnectionInfo{}
+	if c.server == nil {
+		return rv
+	}
+	c.server.conns.Range(func(connID string, st *connState) bool {
+		st.mu.Lock()
+		defer st.mu.Unlock()
+		rv = append(rv, connectionInfo(connID, st))
+		return true
+	})
+	sort.Slice(rv, func(i, j int) bool { return rv[i].ID < rv[j].ID })
+	return rv
+}
+
+// Quarantine 隔离连接：通过 ACLAddReplace 将连接的 ACL 原地替换为隔离规则，其他连接和 NSM 连接本身不受影响
+//
+// 隔离规则默认拒绝全部流量，也可以使用配置的隔离规则集（见 WithQuarantineRuleSet）。
+// 隔离期间策略变化不影响该连接，直到通过 Release 解除隔离。
+//
+// 参数:
+//   - ctx: 上下文
+//   - connID: 连接 ID
+//
+// 返回:
+//   - *ConnectionInfo: 隔离后的连接状态
+//   - error: 连接不存在（ErrNotFound）或下发 ACL 失败，失败时连接保持原状态
+func (c *Controller) Quarantine(ctx context.Context, connID string) (*ConnectionInfo, error) {
+	return c.setQuarantine(ctx, connID, true)
+}
+
+// Release 解除连接的隔离，按当前策略恢复连接的 ACL
+func (c *Controller) Release(ctx context.Context, connID string) (*ConnectionInfo, error) {
+	return c.setQuarantine(ctx, connID, false)
+}
+
+// EmergencyStatus 返回全局紧急模式状态
+func (c *Controller) EmergencyStatus() EmergencyStatus {
+	if c.server == nil {
+		return EmergencyStatus{Mode: EmergencyOff}
+	}
+	return c.server.emergencyStatus()
+}
+
+// SetEmergencyMode 切换全局紧急模式，全部连接的 ACL 替换为拒绝或放行全部流量的规则，
+// 切换回 EmergencyOff 时恢复每个连接原有的规则
+//
+// 参数:
+//   - ctx: 上下文
+//   - mode: 目标紧急模式
+//
+// 返回:
+//   - *EmergencyStatus: 切换后的状态，Failed 为下发 ACL 失败（保留原有 ACL）的连接数
+//   - error: 模式无效（见 ParseEmergencyMode）
+func (c *Controller) SetEmergencyMode(ctx context.Context, mode EmergencyMode) (*EmergencyStatus, error) {
+	if c.server == nil {
+		return nil, errors.New("ACL 控制器未绑定 ACL 服务器")
+	}
+	mode, err := ParseEmergencyMode(string(mode))
+	if err != nil {
+		return nil, err
+	}
+	status := c.server.setEmergency(ctx, mode)
+	return &status, nil
+}
+
+// setQuarantine 隔离或解除隔离连接
+func (c *Controller) setQuarantine(ctx context.Context, connID string, quarantined bool) (*ConnectionInfo, error) {
+	if c.server == nil {
+		return nil, errors.New("ACL 控制器未绑定 ACL 服务器")
+	}
+	st, ok := c.server.conns.Load(connID)
+	if !ok {
+		return nil, errors.Wrapf(ErrNotFound, "连接 %s", connID)
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.closed {
+		return nil, errors.Wrapf(ErrNotFound, "连接 %s", connID)
+	}
+	if err := c.server.setQuarantine(ctx, connID, st, quarantined); err != nil {
+		return nil, err
+	}
+	info := connectionInfo(connID, st)
+	return &info, nil
+}
+
+// ConnectionRules 返回连接已下发的规则，连接不存在时返回 false
+func (c *Controller) ConnectionRules(connID string) (*ConnectionRules, bool) {
+	if c.server == nil {
+		return nil, false
+	}
+	st, ok := c.server.conns.Load(connID)
+	if !ok {
+		return nil, false
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return &ConnectionRules{
+		ID:      connID,
+		Ingress: ruleInfos(st.inRules),
+		Egress:  ruleInfos(st.outRules),
+	}, true
+}
+
+// RuleSets 返回配置的规则集
+func (c *Controller) RuleSets() []RuleSetInfo {
+	rv := []RuleSetInfo{}
+	if c.server == nil {
+		return rv
+	}
+	rules := &ConfiguredRules{RuleSets: c.server.policy.RuleSets(), Temporary: c.server.policy.TemporaryRules()}
+	for _, set := range rules.RuleSets {
+		rv = append(rv, ruleSetInfo(rules, set.Name))
+	}
+	return rv
+}
+
+// AddRule 在规则集的 position 处插入规则，position 小于 0 时追加到末尾
+//
+// 参数:
+//   - ctx: 上下文
+//   - setName: 规则集名称
+//   - position: 插入位置（0 为最前面）
+//   - name: 规则名称，在所有规则集中唯一
+//   - raw: 规则内容，格式见 ParseRule
+//
+// 返回:
+//   - *RuleSetInfo: 修改后的规则集
+//   - error: 规则集不存在（ErrNotFound）、规则或位置无效（ErrInvalidRule）、规则只读（ErrReadOnly）或保存状态失败
+func (c *Controller) AddRule(ctx context.Context, setName string, position int, name string, raw []byte) (*RuleSetInfo, error) {
+	rule, err := ParseRule(name, raw)
+	if err != nil {
+		return nil, invalidRule(err)
+	}
+	return c.updateRuleSet(ctx, setName, func(rules []Rule) ([]Rule, error) {
+		if position < 0 {
+			position = len(rules)
+		}
+		if position > len(rules) {
+			return nil, invalidRule(errors.Errorf("位置 %d 超出规则数 %d", position, len(rules)))
+		}
+		return append(rules[:position], append([]Rule{rule}, rules[position:]...)...), nil
+	})
+}
+
+// AddTemporaryRule 添加属于规则集的临时规则，ttl 后自动删除
+//
+// 临时规则排在全部配置的规则集之前匹配（见 Policy.Rules），可以通过 RemoveRule 提前删除。
+//
+// 参数:
+//   - ctx: 上下文
+//   - setName: 规则所属的规则集名称
+//   - name: 规则名称，在所有规则集和临时规则中唯一
+//   - raw: 规则内容，格式见 ParseRule
+//   - ttl: 规则有效期
+//
+// 返回:
+//   - *RuleSetInfo: 修改后的规则集（含剩余有效期）
+//   - error: 规则集不存在（ErrNotFound）、规则或有效期无效（ErrInvalidRule）、规则只读（ErrReadOnly）或保存状态失败
+func (c *Controller) AddTemporaryRule(ctx context.Context, setName, name string, raw []byte, ttl time.Duration) (*RuleSetInfo, error) {
+	if ttl <= 0 {
+		return nil, invalidRule(errors.Errorf("有效期 %v 无效", ttl))
+	}
+	rule, err := ParseRule(name, raw)
+	if err != nil {
+		return nil, invalidRule(err)
+	}
+	rule.RuleSet = setName
+	var updated *ConfiguredRules
+	err = c.update(ctx, func(rules *ConfiguredRules) error {
+		if findRuleSet(rules.RuleSets, setName) < 0 {
+			return errors.Wrapf(ErrNotFound, "规则集 %q", setName)
+		}
+		rules.Temporary = append(rules.Temporary, TemporaryRule{Rule: rule, ExpiresAt: time.Now().Add(ttl)})
+		updated = rules
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).WithField("acl", "controller").Infof("添加临时规则 %q（规则集 %q），%v 后到期", name, setName, ttl)
+	info := ruleSetInfo(updated, setName)
+	return &info, nil
+}
+
+// TemporaryRules 返回尚未删除的临时规则及其剩余有效期，按匹配顺序排列
+func (c *Controller) TemporaryRules() []TemporaryRuleInfo {
+	if c.server == nil {
+		return []TemporaryRuleInfo{}
+	}
+	return temporaryRuleInfos(c.server.policy.TemporaryRules(), time.Now())
+}
+
+// RemoveRule 从规则集中删除规则，规则集中的临时规则同样可以删除
+func (c *Controller) RemoveRule(ctx context.Context, setName, name string) (*RuleSetInfo, error) {
+	var updated *ConfiguredRules
+	err := c.update(ctx, func(rules *ConfiguredRules) error {
+		i := findRuleSet(rules.RuleSets, setName)
+		if i < 0 {
+			return errors.Wrapf(ErrNotFound, "规则集 %q", setName)
+		}
+		updated = rules
+		set := &rules.RuleSets[i]
+		if j, err := findRule(set.Rules, name); err == nil {
+			set.Rules = append(set.Rules[:j], set.Rules[j+1:]...)
+			return nil
+		}
+		for j := range rules.Temporary {
+			if rules.Temporary[j].Name == name && rules.Temporary[j].RuleSet == setName {
+				rules.Temporary = append(rules.Temporary[:j], rules.Temporary[j+1:]...)
+				return nil
+			}
+		}
+		return errors.Wrapf(ErrNotFound, "规则 %q", name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).WithField("acl", "controller").Infof("从规则集 %q 删除规则 %q", setName, name)
+	info := ruleSetInfo(updated, setName)
+	return &info, nil
+}
+
+// MoveRule 将规则移动到规则集中的 position 处（0 为最前面）
+func (c *Controller) MoveRule(ctx context.Context, setName, name string, position int) (*RuleSetInfo, error) {
+	return c.updateRuleSet(ctx, setName, func(rules []Rule) ([]Rule, error) {
+		i, err := findRule(rules, name)
+		if err != nil {
+			return nil, err
+		}
+		if position < 0 || position >= len(rules) {
+			return nil, invalidRule(errors.Errorf("位置 %d 超出范围 [0, %d]", position, len(rules)-1))
+		}
+		rule := rules[i]
+		rules = append(rules[:i], rules[i+1:]...)
+		return append(rules[:position], append([]Rule{rule}, rules[position:]...)...), nil
+	})
+}
+
+// ReplaceRule 替换规则集中规则的内容，规则名称和位置不变
+func (c *Controller) ReplaceRule(ctx context.Context, setName, name string, raw []byte) (*RuleSetInfo, error) {
+	rule, err := ParseRule(name, raw)
+	if err != nil {
+		return nil, invalidRule(err)
+	}
+	return c.updateRuleSet(ctx, setName, func(rules []Rule) ([]Rule, error) {
+		i, err := findRule(rules, name)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = rule
+		return rules, nil
+	})
+}
+
+// updateRuleSet 原地修改规则集中的规则并更新策略（见 update）
+func (c *Controller) updateRuleSet(ctx context.Context, setName string, fn func(rules []Rule) ([]Rule, error)) (*RuleSetInfo, error) {
+	var updated *ConfiguredRules
+	err := c.update(ctx, func(rules *ConfiguredRules) error {
+		i := findRuleSet(rules.RuleSets, setName)
+		if i < 0 {
+			return errors.Wrapf(ErrNotFound, "规则集 %q", setName)
+		}
+		setRules, err := fn(rules.RuleSets[i].Rules)
+		if err != nil {
+			return err
+		}
+		rules.RuleSets[i].Rules = setRules
+		updated = rules
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	info := ruleSetInfo(updated, setName)
+	log.FromContext(ctx).WithField("acl", "controller").Infof("规则集 %q 已更新，规则 %d 条", setName, len(info.Rules))
+	return &info, nil
+}
+
+// update 修改规则集和临时规则并更新策略
+//
+// 功能说明:
+//   - 规则只读时（见 WithReadOnlyRules）返回 ErrReadOnly，策略不变
+//   - 修改在策略锁内完成（见 Policy.Update），与其他修改互斥
+//   - 修改后的规则通过校验并写入状态文件后才会生效；任一步骤失败时策略不变
+//   - 策略更新后，ACL 服务器通过 ACLAddReplace 原地替换每个连接的 ACL（见 apply）
+func (c *Controller) update(ctx context.Context, fn func(rules *ConfiguredRules) error) error {
+	if c.server == nil {
+		return errors.New("ACL 控制器未绑定 ACL 服务器")
+	}
+	if c.readOnly != "" {
+		return errors.Wrap(ErrReadOnly, c.readOnly)
+	}
+	return c.server.policy.Update(ctx, func(rules *ConfiguredRules) error {
+		if err := fn(rules); err != nil {
+			return err
+		}
+		if err := validateRules(rules); err != nil {
+			return invalidRule(err)
+		}
+		if c.statePath == "" {
+			return nil
+		}
+		return SaveState(c.statePath, c.configDigest, rules)
+	})
+}
+
+// ReloadConfig 以重新加载的配置文件内容替换规则集和临时规则
+//
+// 功能说明:
+//   - 与重启时相同：rules 为配置文件中的规则集，状态文件与配置文件一致时为状态文件中的规则（见 LoadState）
+//   - rules 没有临时规则（配置文件已变化，未加载状态文件）且 keepTemporary 为 true 时，保留当前尚未到期的临时规则
+//     （如通过管理接口添加的事件封禁规则）；所属规则集已不存在或名称与新规则重复的临时规则记录警告后丢弃
+//   - 替换后的规则通过校验并以新的配置文件摘要写入状态文件后才会生效，失败时策略不变
+//   - 策略更新后，ACL 服务器通过 ACLAddReplace 原地替换每个连接的 ACL
+//
+// 参数:
+//   - ctx: 上下文
+//   - digest: 重新加载的配置文件内容的摘要（见 ConfigDigest）
+//   - rules: 重新加载的规则集和临时规则
+//   - keepTemporary: 是否保留当前的临时规则
+//
+// 返回:
+//   - error: 规则无效（ErrInvalidRule）或写入状态文件失败
+func (c *Controller) ReloadConfig(ctx context.Context, digest string, rules *ConfiguredRules, keepTemporary bool) error {
+	if c.server == nil {
+		return errors.New("ACL 控制器未绑定 ACL 服务器")
+	}
+	return c.server.policy.Update(ctx, func(current *ConfiguredRules) error {
+		temporary := rules.Temporary
+		if len(temporary) == 0 && keepTemporary {
+			temporary = carryTemporary(ctx, current.Temporary, rules.RuleSets, time.Now())
+		}
+		current.RuleSets = cloneRuleSets(rules.RuleSets)
+		current.Temporary = append([]TemporaryRule{}, temporary...)
+		if err := validateRules(current); err != nil {
+			return invalidRule(err)
+		}
+		if c.statePath != "" {
+			if err := SaveState(c.statePath, digest, current); err != nil {
+				return err
+			}
+		}
+		c.configDigest = digest
+		return nil
+	})
+}
+
+// ConfigLoaded 报告配置文件中的规则是否已加载并通过校验
+//
+// 启动时配置文件读取、签名校验或规则校验失败，且之后未通过重新加载配置（见 ReloadConfig）恢复时返回 false。
+func (c *Controller) ConfigLoaded() bool {
+	if c.server == nil {
+		return c.configDigest != ""
+	}
+	c.server.policy.mu.RLock()
+	defer c.server.policy.mu.RUnlock()
+	return c.configDigest != ""
+}
+
+// ReconcileDuration 返回进行中最早开始的一次策略同步（策略变化或紧急模式切换后重新下发全部连接的 ACL）
+// 已持续的时间，没有进行中的同步时返回 0
+func (c *Controller) ReconcileDuration() time.Duration {
+	if c.server == nil {
+		return 0
+	}
+	return c.server.reconciles.oldest()
+}
+
+// findRuleSet 返回名称为 name 的规则集的下标，不存在时返回 -1
+func findRuleSet(sets []RuleSet, name string) int {
+	for i := range sets {
+		if sets[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// findRule 返回名称为 name 的规则的下标
+func findRule(rules []Rule, name string) (int, error) {
+	for i := range rules {
+		if rules[i].Name == name {
+			return i, nil
+		}
+	}
+	return 0, errors.Wrapf(ErrNotFound, "规则 %q", name)
+}
+
+// invalidRule 将校验错误标记为 ErrInvalidRule
+func invalidRule(err error) error {
+	return errors.Wrap(ErrInvalidRule, err.Error())
+}
+
+// ruleSetInfo 将规则集 name 及属于它的临时规则转换为可读形式，规则集须存在
+func ruleSetInfo(rules *ConfiguredRules, name string) RuleSetInfo {
+	set := rules.RuleSets[findRuleSet(rules.RuleSets, name)]
+	temporary := []TemporaryRuleInfo{}
+	for _, info := range temporaryRuleInfos(rules.Temporary, time.Now()) {
+		if info.RuleSet == name {
+			temporary = append(temporary, info)
+		}
+	}
+	return RuleSetInfo{
+		Name:        set.Name,
+		Stateful:    set.Stateful,
+		FailureMode: set.FailureMode,
+		Rules:       setRuleInfos(set),
+		Temporary:   temporary,
+	}
+}
+
+// setRuleInfos 将规则集中的规则转换为可读形式，使用前缀模板的规则显示模板
+func setRuleInfos(set RuleSet) []RuleInfo {
+	rules := Flatten([]RuleSet{set})
+	infos := ruleInfos(rules)
+	for i := range rules {
+		if rules[i].SrcPrefixTemplate != "" {
+			infos[i].Src = rules[i].SrcPrefixTemplate
+		}
+		if rules[i].DstPrefixTemplate != "" {
+			infos[i].Dst = rules[i].DstPrefixTemplate
+		}
+	}
+	return infos
+}
+
+// temporaryRuleInfos 将临时规则转换为可读形式，剩余有效期精确到秒
+func temporaryRuleInfos(temporary []TemporaryRule, now time.Time) []TemporaryRuleInfo {
+	rv := make([]TemporaryRuleInfo, 0, len(temporary))
+	for i := range temporary {
+		r := &temporary[i]
+		info := setRuleInfos(RuleSet{Name: r.RuleSet, Rules: []Rule{r.Rule}})[0]
+		info.Position = i
+		remaining := r.ExpiresAt.Sub(now).Round(time.Second)
+		if remaining < 0 {
+			remaining = 0
+		}
+		rv = append(rv, TemporaryRuleInfo{RuleInfo: info, ExpiresAt: r.ExpiresAt, Remaining: remaining.String()})
+	}
+	return rv
+}
+
+// connectionInfo 返回连接的 ACL 状态，调用方需持有 st.mu
+func connectionInfo(connID string, st *connState) ConnectionInfo {
+	info := ConnectionInfo{
+		ID:             connID,
+		NetworkService: st.service,
+		Path:           append([]string{}, st.path...),
+		SwIfIndex:      uint32(st.swIfIndex),
+		RuleSets:       ruleSetNames(st.inRules, st.outRules),
+		Ingress:        append([]uint32{}, st.ingress...),
+		Egress:         append([]uint32{}, st.egress...),
+		Degraded:       st.degraded,
+		Quarantined:    st.quarantined,
+	}
+	if len(st.path) > 0 {
+		info.NSCName = st.path[0]
+	}
+	return info
+}
+
+// pathNames 返回连接路径上各段的名称
+func pathNames(conn *networkservice.Connection) []string {
+	segments := conn.GetPath().GetPathSegments()
+	names := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		names = append(names, segment.GetName())
+	}
+	return names
+}
+
+// ruleSetNames 按规则顺序返回去重后的规则集名称
+func ruleSetNames(lists ...[]Rule) []string {
+	names := []string{}
+	seen := make(map[string]bool)
+	for _, rules := range lists {
+		for i := range rules {
+			if name := rules[i].RuleSet; name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// ruleInfos 将规则转换为可读形式
+func ruleInfos(rules []Rule) []RuleInfo {
+	rv := make([]RuleInfo, 0, len(rules))
+	for i := range rules {
+		r := &rules[i]
+		rv = append(rv, RuleInfo{
+			Position:      i,
+			Name:          r.Name,
+			RuleSet:       r.RuleSet,
+			Action:        actionName(r.IsPermit),
+			Proto:         uint8(r.Proto),
+			Src:           r.SrcPrefix.String(),
+			Dst:           r.DstPrefix.String(),
+			SrcPorts:      portRange(r.SrcportOrIcmptypeFirst, r.SrcportOrIcmptypeLast),
+			DstPorts:      portRange(r.DstportOrIcmpcodeFirst, r.DstportOrIcmpcodeLast),
+			TCPFlagsMask:  r.TCPFlagsMask,
+			TCPFlagsValue: r.TCPFlagsValue,
+		})
+	}
+	return rv
+}
+
+// portRange 返回端口（或 ICMP 类型/代码）范围的可读形式
+func portRange(first, last uint16) string {
+	if first == last {
+		return fmt.Sprint(first)
+	}
+	return fmt.Sprintf("%d-%d", first, last)
+}
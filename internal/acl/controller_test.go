@@ -0,0 +1,143 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+	"github.com/ifzzh/cmd-nse-template/internal/acl/acltest"
+	"github.com/pkg/errors"
+)
+
+func TestReloadConfigKeepsTemporaryRules(t *testing.T) {
+	ctx := context.Background()
+	controller := acl.NewController()
+	policy := acl.NewPolicy(parseRuleSets(t, `
+ruleSets:
+  - name: incidents
+    rules:
+      allow all:
+        ispermit: 1
+  - name: legacy
+    rules:
+      allow legacy:
+        ispermit: 1
+`))
+	_ = newTestServer(acltest.NewBackend(), policy, acl.WithController(controller))
+
+	for _, tc := range []struct{ set, name string }{{"incidents", "block attacker"}, {"legacy", "block legacy"}} {
+		if _, err := controller.AddTemporaryRule(ctx, tc.set, tc.name, []byte("srcprefix: 192.0.2.1/32\nispermit: 0\n"), time.Hour); err != nil {
+			t.Fatalf("添加临时规则 %q 失败: %v", tc.name, err)
+		}
+	}
+
+	// 配置文件已变化：删除 legacy 规则集，未加载状态文件（没有临时规则）
+	reloaded := &acl.ConfiguredRules{RuleSets: parseRuleSets(t, `
+ruleSets:
+  - name: incidents
+    rules:
+      allow web:
+        dstportoricmpcodefirst: 80
+        dstportoricmpcodelast: 80
+        ispermit: 1
+`)}
+	if err := controller.ReloadConfig(ctx, "digest-2", reloaded, true); err != nil {
+		t.Fatalf("ReloadConfig 失败: %v", err)
+	}
+
+	temporary := controller.TemporaryRules()
+	if len(temporary) != 1 || temporary[0].Name != "block attacker" {
+		t.Fatalf("临时规则 = %+v，期望只保留规则集仍然存在的 \"block attacker\"", temporary)
+	}
+	if !controller.ConfigLoaded() {
+		t.Error("重新加载配置后 ConfigLoaded 为 false")
+	}
+
+	if err := controller.ReloadConfig(ctx, "digest-3", reloaded, false); err != nil {
+		t.Fatalf("ReloadConfig 失败: %v", err)
+	}
+	if temporary = controller.TemporaryRules(); len(temporary) != 0 {
+		t.Fatalf("临时规则 = %+v，期望全部丢弃", temporary)
+	}
+}
+
+func TestReadOnlyRulesRejectRuntimeChanges(t *testing.T) {
+	ctx := context.Background()
+	controller := acl.NewController(acl.WithReadOnlyRules("已配置策略签名校验"))
+	policy := acl.NewPolicy(parseRuleSets(t, `
+ruleSets:
+  - name: web
+    rules:
+      allow all:
+        ispermit: 1
+`))
+	_ = newTestServer(acltest.NewBackend(), policy, acl.WithController(controller))
+
+	raw := []byte("srcprefix: 192.0.2.1/32\nispermit: 0\n")
+	for name, fn := range map[string]func() error{
+		"AddRule": func() error {
+			_, err := controller.AddRule(ctx, "web", 0, "block attacker", raw)
+			return err
+		},
+		"AddTemporaryRule": func() error {
+			_, err := controller.AddTemporaryRule(ctx, "web", "block attacker", raw, time.Hour)
+			return err
+		},
+		"RemoveRule": func() error {
+			_, err := controller.RemoveRule(ctx, "web", "allow all")
+			return err
+		},
+		"MoveRule": func() error {
+			_, err := controller.MoveRule(ctx, "web", "allow all", 0)
+			return err
+		},
+		"ReplaceRule": func() error {
+			_, err := controller.ReplaceRule(ctx, "web", "allow all", raw)
+			return err
+		},
+	} {
+		if err := fn(); !errors.Is(err, acl.ErrReadOnly) {
+			t.Errorf("%s 返回 %v，期望 ErrReadOnly", name, err)
+		}
+	}
+
+	sets := controller.RuleSets()
+	if len(sets) != 1 || len(sets[0].Rules) != 1 || sets[0].Rules[0].Name != "allow all" || len(sets[0].Temporary) != 0 {
+		t.Fatalf("规则集 = %+v，期望保持不变", sets)
+	}
+
+	// 重新加载签名校验通过的配置文件不受影响
+	reloaded := &acl.ConfiguredRules{RuleSets: parseRuleSets(t, `
+ruleSets:
+  - name: web
+    rules:
+      deny all:
+        ispermit: 0
+`)}
+	if err := controller.ReloadConfig(ctx, "digest-2", reloaded, false); err != nil {
+		t.Fatalf("ReloadConfig 失败: %v", err)
+	}
+	if sets = controller.RuleSets(); len(sets) != 1 || len(sets[0].Rules) != 1 || sets[0].Rules[0].Name != "deny all" {
+		t.Fatalf("重新加载后规则集 = %+v，期望为新配置", sets)
+	}
+}
@@ -0,0 +1,354 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/govpp/binapi/acl"
+	"github.com/networkservicemesh/govpp/binapi/interface_types"
+	"github.com/pkg/errors"
+	"go.fd.io/govpp/adapter"
+	"go.fd.io/govpp/adapter/statsclient"
+	"go.fd.io/govpp/api"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
+)
+
+const (
+	// directionIngress 入站方向（VPP 接口输入 ACL）
+	directionIngress = "ingress"
+	// directionEgress 出站方向（VPP 接口输出 ACL）
+	directionEgress = "egress"
+
+	// aclStatsPattern 统计段中 ACL 逐条规则命中计数的名称模式（/acl/<acl_index>/matches）
+	aclStatsPattern = `^/acl/[0-9]+/matches$`
+)
+
+// RuleHits 单条规则的命中计数快照
+type RuleHits struct {
+	ConnectionID string `json:"connectionId"`
+	Direction    string `json:"direction"`
+	ACLIndex     uint32 `json:"aclIndex"`
+	Position     int    `json:"position"`
+	Rule         string `json:"rule"`
+	Action       string `json:"action"`
+	Packets      uint64 `json:"packets"`
+	Bytes        uint64 `json:"bytes"`
+}
+
+// trackedACL 一个由 ACL 服务器创建、需要采集计数的 VPP ACL
+type trackedACL struct {
+	connID    string
+	direction string
+	offset    int
+	rules     []Rule
+	packets   []uint64
+	bytes     []uint64
+}
+
+// RuleCounters 采集本元素所创建 ACL 的逐条规则命中计数
+//
+// 功能说明:
+//   - 首次使用时通过 ACLStatsIntfCountersEnable 开启 VPP ACL 接口计数
+//   - 定期从 VPP 统计段读取 /acl/<acl_index>/matches 计数
+//   - 将计数按规则名称、方向、连接 ID 和动作导出到 OpenTelemetry
+//   - 定期读取有状态规则所在连接的 ACL 插件会话数
+//   - 提供快照供管理端点查询
+type RuleCounters struct {
+	vppConn     api.Connection
+	statsSocket string
+	interval    time.Duration
+
+	enableMu sync.Mutex
+	enabled  bool
+
+	mu         sync.RWMutex
+	acls       map[uint32]*trackedACL
+	activity   map[ruleKey]*ruleActivity
+	interfaces map[string]interface_types.InterfaceIndex
+	sessions   map[string]uint64
+}
+
+// NewRuleCounters 创建规则命中计数采集器，并在后台按 interval 周期采集
+//
+// 参数:
+//   - ctx: 上下文，取消时停止采集
+//   - vppConn: VPP API 连接，用于开启 ACL 计数
+//   - statsSocket: VPP 统计段 socket 路径，为空时使用默认路径
+//   - interval: 采集周期
+//
+// 返回:
+//   - *RuleCounters: 计数采集器，通过 WithRuleCounters 传给 ACL 服务器
+func NewRuleCounters(ctx context.Context, vppConn api.Connection, statsSocket string, interval time.Duration) *RuleCounters {
+	if statsSocket == "" {
+		statsSocket = adapter.DefaultStatsSocket
+	}
+	c := &RuleCounters{
+		vppConn:     vppConn,
+		statsSocket: statsSocket,
+		interval:    interval,
+		acls:        make(map[uint32]*trackedACL),
+		activity:    make(map[ruleKey]*ruleActivity),
+		interfaces:  make(map[string]interface_types.InterfaceIndex),
+		sessions:    make(map[string]uint64),
+	}
+	if opentelemetry.IsEnabled() {
+		c.registerMetrics(ctx)
+		c.registerSessionMetrics(ctx)
+	}
+	go c.run(ctx)
+	return c
+}
+
+// enable 开启 VPP ACL 接口计数，成功后不再重复调用
+func (c *RuleCounters) enable(ctx context.Context) {
+	c.enableMu.Lock()
+	defer c.enableMu.Unlock()
+	if c.enabled {
+		return
+	}
+	if _, err := acl.NewServiceClient(c.vppConn).ACLStatsIntfCountersEnable(ctx, &acl.ACLStatsIntfCountersEnable{Enable: true}); err != nil {
+		log.FromContext(ctx).WithField("acl_counters", "enable").Warnf("开启 VPP ACL 计数失败: %v", err)
+		return
+	}
+	c.enabled = true
+}
+
+// declare 登记配置中的规则，用于生成未命中规则报告
+//
+// 每次按当前规则重建登记表：仍然存在的规则（规则集和名称均相同）沿用已有的观测起点和最后命中时间，
+// 已删除或改名的规则不再出现在报告中，新增的规则从现在开始观测。
+func (c *RuleCounters) declare(rules []Rule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	activity := make(map[ruleKey]*ruleActivity, len(rules))
+	for i := range rules {
+		key := keyOf(&rules[i])
+		if _, ok := activity[key]; ok {
+			continue
+		}
+		act, ok := c.activity[key]
+		if !ok {
+			act = &ruleActivity{since: now}
+		}
+		act.order = len(activity)
+		act.action = actionName(rules[i].IsPermit)
+		activity[key] = act
+	}
+	c.activity = activity
+}
+
+// track 登记一个需要采集计数的 ACL
+//
+// 规则集被切分为多个 ACL 时，offset 为该 ACL 第一条规则在整个方向规则列表中的位置。
+func (c *RuleCounters) track(connID, direction string, aclIndex uint32, offset int, rules []Rule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &trackedACL{
+		connID:    connID,
+		direction: direction,
+		offset:    offset,
+		rules:     rules,
+		packets:   make([]uint64, len(rules)),
+		bytes:     make([]uint64, len(rules)),
+	}
+	// 原地替换规则后 VPP 中的计数不会清零，同一位置的同名规则沿用已采集的计数，
+	// 避免把替换前的命中当作新的命中
+	if prev, ok := c.acls[aclIndex]; ok {
+		for pos := range rules {
+			if pos < len(prev.rules) && prev.rules[pos].Name == rules[pos].Name {
+				t.packets[pos], t.bytes[pos] = prev.packets[pos], prev.bytes[pos]
+			}
+		}
+	}
+	c.acls[aclIndex] = t
+}
+
+// untrack 注销 ACL，删除 ACL 前调用
+func (c *RuleCounters) untrack(aclIndex uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.acls, aclIndex)
+}
+
+// Snapshot 返回当前所有规则的命中计数，按连接、方向和规则位置排序
+func (c *RuleCounters) Snapshot() []RuleHits {
+	c.mu.RLock()
+	var rv []RuleHits
+	for index, t := range c.acls {
+		for pos := range t.rules {
+			rv = append(rv, RuleHits{
+				ConnectionID: t.connID,
+				Direction:    t.direction,
+				ACLIndex:     index,
+				Position:     t.offset + pos,
+				Rule:         t.rules[pos].Name,
+				Action:       actionName(t.rules[pos].IsPermit),
+				Packets:      t.packets[pos],
+				Bytes:        t.bytes[pos],
+			})
+		}
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(rv, func(i, j int) bool {
+		if rv[i].ConnectionID != rv[j].ConnectionID {
+			return rv[i].ConnectionID < rv[j].ConnectionID
+		}
+		if rv[i].Direction != rv[j].Direction {
+			return rv[i].Direction > rv[j].Direction
+		}
+		return rv[i].Position < rv[j].Position
+	})
+	return rv
+}
+
+// run 周期性地从 VPP 统计段读取计数，ctx 结束时断开统计连接
+func (c *RuleCounters) run(ctx context.Context) {
+	logger := log.FromContext(ctx).WithField("acl_counters", "run")
+
+	var stats *statsclient.StatsClient
+	defer func() {
+		if stats != nil {
+			_ = stats.Disconnect()
+		}
+	}()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := c.collectSessions(ctx); err != nil {
+			logger.Debugf("读取 ACL 会话数失败: %v", err)
+		}
+		if stats == nil {
+			sc := statsclient.NewStatsClient(c.statsSocket)
+			if err := sc.Connect(); err != nil {
+				logger.Debugf("连接 VPP 统计段失败: %v", err)
+				continue
+			}
+			stats = sc
+		}
+		if err := c.collect(stats); err != nil {
+			logger.Debugf("读取 ACL 计数失败: %v", err)
+			_ = stats.Disconnect()
+			stats = nil
+		}
+	}
+}
+
+// collect 读取一次 ACL 计数并更新已登记的 ACL
+//
+// 技术细节:
+//
+//	统计段中每个 ACL 对应一个 combined counter 向量，按 [worker 线程][规则位置] 组织，
+//	单条规则的命中数为所有线程计数之和。
+func (c *RuleCounters) collect(stats adapter.StatsAPI) error {
+	entries, err := stats.DumpStats(aclStatsPattern)
+	if err != nil {
+		return errors.Wrap(err, "DumpStats 调用失败")
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range entries {
+		var index uint32
+		if _, scanErr := fmt.Sscanf(string(entries[i].Name), "/acl/%d/matches", &index); scanErr != nil {
+			continue
+		}
+		t, ok := c.acls[index]
+		if !ok {
+			continue
+		}
+		data, ok := entries[i].Data.(adapter.CombinedCounterStat)
+		if !ok {
+			continue
+		}
+		for pos := range t.rules {
+			var packets, bytes uint64
+			for thread := range data {
+				if pos < len(data[thread]) {
+					packets += data[thread][pos].Packets()
+					bytes += data[thread][pos].Bytes()
+				}
+			}
+			if packets > t.packets[pos] {
+				if act, ok := c.activity[keyOf(&t.rules[pos])]; ok {
+					act.lastHit = now
+				}
+			}
+			t.packets[pos], t.bytes[pos] = packets, bytes
+		}
+	}
+	return nil
+}
+
+// registerMetrics 将规则命中计数注册为 OpenTelemetry 异步计数器
+func (c *RuleCounters) registerMetrics(ctx context.Context) {
+	logger := log.FromContext(ctx).WithField("acl_counters", "metrics")
+	meter := otel.Meter("")
+
+	packets, err := meter.Int64ObservableCounter("acl_rule_match_packets",
+		metric.WithDescription("Number of packets matched by a firewall ACL rule"))
+	if err != nil {
+		logger.Errorf("创建指标失败: %v", err)
+		return
+	}
+	bytes, err := meter.Int64ObservableCounter("acl_rule_match_bytes",
+		metric.WithDescription("Number of bytes matched by a firewall ACL rule"),
+		metric.WithUnit("By"))
+	if err != nil {
+		logger.Errorf("创建指标失败: %v", err)
+		return
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, hit := range c.Snapshot() {
+			attrs := metric.WithAttributes(
+				attribute.String("rule", hit.Rule),
+				attribute.String("direction", hit.Direction),
+				attribute.String("connection", hit.ConnectionID),
+				attribute.String("action", hit.Action),
+			)
+			o.ObserveInt64(packets, int64(hit.Packets), attrs)
+			o.ObserveInt64(bytes, int64(hit.Bytes), attrs)
+		}
+		return nil
+	}, packets, bytes)
+	if err != nil {
+		logger.Errorf("注册指标回调失败: %v", err)
+	}
+}
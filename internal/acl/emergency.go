@@ -0,0 +1,255 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/govpp/binapi/acl_types"
+	"github.com/networkservicemesh/govpp/binapi/ip_types"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/extend"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
+)
+
+// emergencyReminderInterval 紧急模式生效期间重复记录警告日志的间隔
+const emergencyReminderInterval = time.Minute
+
+// EmergencyMode 全局紧急模式
+type EmergencyMode string
+
+const (
+	// EmergencyOff 未启用紧急模式，连接按策略（或隔离规则）下发 ACL
+	EmergencyOff EmergencyMode = "off"
+	// EmergencyDenyAll 封锁模式：全部连接拒绝全部流量
+	EmergencyDenyAll EmergencyMode = "deny-all"
+	// EmergencyAllowAll 应急放行模式：策略有误导致故障时，未隔离的连接放行全部流量
+	EmergencyAllowAll EmergencyMode = "allow-all"
+)
+
+// ParseEmergencyMode 解析紧急模式，空字符串视为 EmergencyOff
+func ParseEmergencyMode(s string) (EmergencyMode, error) {
+	switch mode := EmergencyMode(s); mode {
+	case "":
+		return EmergencyOff, nil
+	case EmergencyOff, EmergencyDenyAll, EmergencyAllowAll:
+		return mode, nil
+	default:
+		return "", errors.Errorf("无效的紧急模式 %q，可选值: %s、%s、%s", s, EmergencyOff, EmergencyDenyAll, EmergencyAllowAll)
+	}
+}
+
+// EmergencyStatus 紧急模式状态
+//
+// 字段说明:
+//   - Mode: 当前紧急模式
+//   - Since: 进入当前模式的时间，未启用时为空
+//   - Connections: 最近一次切换时重新下发 ACL 的连接数
+//   - Failed: 最近一次切换时下发 ACL 失败的连接数，失败的连接保留原有 ACL
+type EmergencyStatus struct {
+	Mode        EmergencyMode `json:"mode"`
+	Since       time.Time     `json:"since,omitempty"`
+	Connections int           `json:"connections"`
+	Failed      int           `json:"failed"`
+}
+
+// emergencyState ACL 服务器的紧急模式状态
+//
+// switchMu 串行化模式切换；mu 保护其余字段，apply 在持有连接锁时读取当前模式。
+type emergencyState struct {
+	switchMu       sync.Mutex
+	mu             sync.RWMutex
+	status         EmergencyStatus
+	cancelReminder context.CancelFunc
+	listeners      []func(ctx context.Context, mode EmergencyMode)
+}
+
+// mode 返回当前紧急模式
+func (e *emergencyState) mode() EmergencyMode {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.status.Mode
+}
+
+var (
+	emergencyMetricOnce sync.Once
+	emergencyMetric     metric.Int64UpDownCounter
+)
+
+// addEmergency 调整紧急模式指标（生效的模式为 1），OpenTelemetry 未启用时不记录
+func addEmergency(ctx context.Context, mode EmergencyMode, delta int64) {
+	emergencyMetricOnce.Do(func() {
+		if !opentelemetry.IsEnabled() {
+			return
+		}
+		counter, err := otel.Meter("").Int64UpDownCounter("acl_emergency_mode",
+			metric.WithDescription("Active global emergency mode of the firewall (1 while the mode is active)"))
+		if err != nil {
+			log.FromContext(ctx).WithField("acl", "metrics").Errorf("创建指标失败: %v", err)
+			return
+		}
+		emergencyMetric = counter
+	})
+	if emergencyMetric != nil {
+		emergencyMetric.Add(ctx, delta, metric.WithAttributes(attribute.String("mode", string(mode))))
+	}
+}
+
+// activeRules 按紧急模式和隔离状态选择连接的规则，调用方需持有 st.mu
+//
+// 优先级: 封锁模式 > 隔离 > 应急放行模式 > 策略规则。隔离的连接在应急放行模式下仍然隔离。
+func (a *aclServer) activeRules(ctx context.Context, st *connState) []Rule {
+	switch mode := a.emergency.mode(); {
+	case mode == EmergencyDenyAll:
+		return matchAllRules(string(mode), acl_types.ACL_ACTION_API_DENY)
+	case st.quarantined:
+		return a.quarantineRules(ctx)
+	case mode == EmergencyAllowAll:
+		return matchAllRules(string(mode), acl_types.ACL_ACTION_API_PERMIT)
+	default:
+		return a.rules(st.service)
+	}
+}
+
+// setEmergency 切换紧急模式并重新下发全部连接的 ACL
+//
+// 功能说明:
+//   - 进入封锁或应急放行模式时，全部连接的 ACL 通过 ACLAddReplace 替换为拒绝或放行全部流量的规则
+//   - 退出时按当前策略重新下发，恢复每个连接原有的规则（隔离的连接恢复隔离规则）
+//   - 模式生效期间定期记录警告日志，并导出 acl_emergency_mode 指标、通知订阅者（如更新 NSE 标签）
+//   - 订阅者在释放 switchMu 之后调用，订阅者阻塞不影响之后的模式切换
+//   - 模式不变时只返回当前状态
+//
+// 参数:
+//   - ctx: 上下文
+//   - mode: 目标紧急模式
+//
+// 返回:
+//   - EmergencyStatus: 切换后的状态，含下发失败的连接数
+func (a *aclServer) setEmergency(ctx context.Context, mode EmergencyMode) EmergencyStatus {
+	e := &a.emergency
+	e.switchMu.Lock()
+
+	e.mu.Lock()
+	previous := e.status.Mode
+	if previous == mode {
+		status := e.status
+		e.mu.Unlock()
+		e.switchMu.Unlock()
+		return status
+	}
+	e.status = EmergencyStatus{Mode: mode}
+	if mode != EmergencyOff {
+		e.status.Since = time.Now()
+	}
+	if e.cancelReminder != nil {
+		e.cancelReminder()
+		e.cancelReminder = nil
+	}
+	e.mu.Unlock()
+
+	logger := log.FromContext(ctx).WithField("acl_server", "emergency").WithField("emergency", mode)
+	if mode == EmergencyOff {
+		logger.Warnf("退出紧急模式 %s，恢复各连接的规则", previous)
+	} else {
+		logger.Warnf("进入紧急模式 %s（原模式 %s），替换全部连接的规则", mode, previous)
+	}
+	if previous != EmergencyOff {
+		addEmergency(ctx, previous, -1)
+	}
+	if mode != EmergencyOff {
+		addEmergency(ctx, mode, 1)
+	}
+
+	total, failed := a.applyAll(ctx)
+	if failed > 0 {
+		logger.Errorf("%d/%d 个连接下发 ACL 失败，这些连接保留原有 ACL", failed, total)
+	}
+
+	e.mu.Lock()
+	e.status.Connections, e.status.Failed = total, failed
+	status := e.status
+	if mode != EmergencyOff {
+		reminderCtx, cancel := context.WithCancel(extend.WithValuesFromContext(context.Background(), ctx))
+		e.cancelReminder = cancel
+		go remindEmergency(reminderCtx, status)
+	}
+	listeners := append([]func(ctx context.Context, mode EmergencyMode){}, e.listeners...)
+	e.mu.Unlock()
+	e.switchMu.Unlock()
+
+	for _, fn := range listeners {
+		fn(ctx, mode)
+	}
+	return status
+}
+
+// emergencyStatus 返回当前紧急模式状态
+func (a *aclServer) emergencyStatus() EmergencyStatus {
+	a.emergency.mu.RLock()
+	defer a.emergency.mu.RUnlock()
+	return a.emergency.status
+}
+
+// remindEmergency 紧急模式生效期间定期记录警告日志，ctx 结束（模式切换）时停止
+func remindEmergency(ctx context.Context, status EmergencyStatus) {
+	ticker := time.NewTicker(emergencyReminderInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			log.FromContext(ctx).WithField("acl_server", "emergency").WithField("emergency", status.Mode).
+				Warnf("紧急模式 %s 生效中，已持续 %v", status.Mode, time.Since(status.Since).Round(time.Second))
+		}
+	}
+}
+
+// matchAllRules 创建匹配 IPv4 和 IPv6 全部流量的规则，source 同时作为规则名称前缀和来源（RuleSet）
+func matchAllRules(source string, action acl_types.ACLAction) []Rule {
+	name := source + " deny"
+	if action != acl_types.ACL_ACTION_API_DENY {
+		name = source + " permit"
+	}
+	rules := make([]Rule, 0, 2)
+	for _, af := range []struct {
+		name string
+		af   ip_types.AddressFamily
+	}{{"ipv4", ip_types.ADDRESS_IP4}, {"ipv6", ip_types.ADDRESS_IP6}} {
+		rules = append(rules, Rule{
+			Name:    name + " " + af.name,
+			RuleSet: source,
+			ACLRule: acl_types.ACLRule{
+				IsPermit:  action,
+				SrcPrefix: anyPrefix(af.af),
+				DstPrefix: anyPrefix(af.af),
+			},
+		})
+	}
+	return rules
+}
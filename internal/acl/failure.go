@@ -0,0 +1,172 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/extend"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
+)
+
+// defaultDegradedRetryInterval 降级连接后台重试下发 ACL 的默认间隔
+const defaultDegradedRetryInterval = 5 * time.Second
+
+// FailureMode ACL 下发失败时连接的处理方式
+type FailureMode string
+
+const (
+	// FailureClosed 关闭连接并返回错误（默认）
+	FailureClosed FailureMode = "closed"
+	// FailureOpen 连接照常建立但不过滤流量，标记为降级，并在后台重试下发 ACL
+	FailureOpen FailureMode = "open"
+)
+
+// UnmarshalYAML 解析并校验失败模式
+func (m *FailureMode) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	switch mode := FailureMode(s); mode {
+	case FailureClosed, FailureOpen:
+		*m = mode
+		return nil
+	default:
+		return errors.Errorf("无效的 failureMode %q，可选值: %s、%s", s, FailureClosed, FailureOpen)
+	}
+}
+
+// failureMode 计算连接的失败模式
+//
+// 只要有一条规则所属的规则集为 closed，连接就按 closed 处理；全部规则集都为 open 时
+// 才按 open 处理。不属于规则集的规则（如黑名单规则）不影响结果。
+func failureMode(rules []Rule) FailureMode {
+	mode := FailureClosed
+	for i := range rules {
+		switch rules[i].FailureMode {
+		case FailureClosed:
+			return FailureClosed
+		case FailureOpen:
+			mode = FailureOpen
+		}
+	}
+	return mode
+}
+
+var (
+	degradedMetricOnce sync.Once
+	degradedMetric     metric.Int64UpDownCounter
+)
+
+// addDegraded 调整降级连接数指标，OpenTelemetry 未启用时不记录
+func addDegraded(ctx context.Context, delta int64) {
+	degradedMetricOnce.Do(func() {
+		if !opentelemetry.IsEnabled() {
+			return
+		}
+		counter, err := otel.Meter("").Int64UpDownCounter("acl_degraded_connections",
+			metric.WithDescription("Number of fail-open connections established without ACL filtering"))
+		if err != nil {
+			log.FromContext(ctx).WithField("acl", "metrics").Errorf("创建指标失败: %v", err)
+			return
+		}
+		degradedMetric = counter
+	})
+	if degradedMetric != nil {
+		degradedMetric.Add(ctx, delta, metric.WithAttributes(attribute.String("mode", string(FailureOpen))))
+	}
+}
+
+// degrade 按 fail-open 模式将连接标记为降级，并在后台重试下发 ACL，调用方需持有 st.mu
+//
+// 参数:
+//   - ctx: 请求上下文，后台重试沿用其中的值（日志、重试策略等），不受其截止时间约束
+//   - connID: 连接 ID
+//   - st: 连接 ACL 状态
+//   - cause: ACL 下发失败的原因
+func (a *aclServer) degrade(ctx context.Context, connID string, st *connState, cause error) {
+	log.FromContext(ctx).
+		WithField("acl_server", "degrade").
+		WithField("connection", connID).
+		WithField("degraded", true).
+		Warnf("ACL 下发失败，按 fail-open 模式建立未过滤的连接，后台每 %v 重试: %v", a.degradedRetryInterval, cause)
+	if st.degraded {
+		return
+	}
+	st.degraded = true
+	addDegraded(ctx, 1)
+
+	retryCtx, cancel := context.WithCancel(extend.WithValuesFromContext(context.Background(), ctx))
+	st.cancelRetry = cancel
+	go a.retryDegraded(retryCtx, connID, st)
+}
+
+// recoverDegraded 清除连接的降级标记并停止后台重试，调用方需持有 st.mu
+func (a *aclServer) recoverDegraded(ctx context.Context, connID string, st *connState) {
+	if !st.degraded {
+		return
+	}
+	st.degraded = false
+	st.cancelRetry()
+	st.cancelRetry = nil
+	addDegraded(ctx, -1)
+	if !st.closed {
+		log.FromContext(ctx).
+			WithField("acl_server", "recover").
+			WithField("connection", connID).
+			Info("ACL 下发成功，连接不再降级")
+	}
+}
+
+// retryDegraded 定期重试下发降级连接的 ACL，直到成功或连接关闭
+func (a *aclServer) retryDegraded(ctx context.Context, connID string, st *connState) {
+	ticker := time.NewTicker(a.degradedRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		st.mu.Lock()
+		if ctx.Err() != nil || st.closed {
+			st.mu.Unlock()
+			return
+		}
+		err := a.apply(ctx, connID, st)
+		st.mu.Unlock()
+		if err != nil {
+			log.FromContext(ctx).
+				WithField("acl_server", "retryDegraded").
+				WithField("connection", connID).
+				WithField("degraded", true).
+				Warnf("重试下发 ACL 失败: %v", err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+	"time"
+)
+
+// Option ACL 服务器的可选配置项
+type Option func(a *aclServer)
+
+// WithBackend 替换 ACL 编程后端
+//
+// 默认后端通过 VPP 二进制 API 编程 ACL，测试中可以传入 acltest.NewBackend()，
+// 在没有 VPP 的情况下运行完整的 Request/Close 流程。
+func WithBackend(backend ACLBackend) Option {
+	return func(a *aclServer) {
+		a.backend = backend
+	}
+}
+
+// WithRuleCounters 为 ACL 服务器设置规则命中计数采集器
+//
+// 设置后，服务器会开启 VPP ACL 接口计数，并登记为每个连接创建的 ACL，
+// 以便按规则名称、方向、连接 ID 和动作导出命中计数。
+func WithRuleCounters(counters *RuleCounters) Option {
+	return func(a *aclServer) {
+		a.counters = counters
+	}
+}
+
+// WithRetryPolicy 设置 ACL 编程失败时的重试策略
+//
+// 默认不重试：ACLAddReplace 或 ACLInterfaceSetACLList 失败时立即关闭连接。
+// 设置后，可重试的瞬时错误会在回滚已创建的 ACL 后按退避时间重试。
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(a *aclServer) {
+		a.retryPolicy = policy
+	}
+}
+
+// WithPosition 设置本元素 ACL 在接口入站/出站 ACL 列表中的位置
+//
+// 默认 PositionPrepend：本元素的规则先于其他组件的 ACL 匹配。
+func WithPosition(position Position) Option {
+	return func(a *aclServer) {
+		a.position = position
+	}
+}
+
+// WithAntiSpoof 开启自动防伪造规则
+//
+// 开启后，每个连接的入站 ACL 最前面会加入拒绝规则，丢弃源地址不属于
+// 连接 IpContext.SrcIpAddrs 的流量。
+func WithAntiSpoof() Option {
+	return func(a *aclServer) {
+		a.antiSpoof = true
+	}
+}
+
+// WithChunkSize 设置每个 ACL 的规则数上限
+//
+// 规则数超过 size 时，每个方向的规则按顺序切分为多个 ACL，依次绑定到接口；
+// size 小于等于 0 表示不切分。
+func WithChunkSize(size int) Option {
+	return func(a *aclServer) {
+		a.chunkSize = size
+	}
+}
+
+// WithDegradedRetryInterval 设置 fail-open 降级连接后台重试下发 ACL 的间隔
+//
+// 默认 5 秒。只有全部规则集都配置 failureMode: open 时，ACL 下发失败的连接才会降级。
+func WithDegradedRetryInterval(interval time.Duration) Option {
+	return func(a *aclServer) {
+		a.degradedRetryInterval = interval
+	}
+}
+
+// WithQuarantineRuleSet 设置隔离连接使用的规则集
+//
+// 该规则集只用于被隔离的连接，不下发给其他连接；未设置或规则集不存在时，隔离连接拒绝全部流量。
+func WithQuarantineRuleSet(name string) Option {
+	return func(a *aclServer) {
+		a.quarantineRuleSet = name
+	}
+}
+
+// WithServiceRuleSets 按请求的网络服务选择下发的规则集
+//
+// services 为网络服务名称到规则集名称的映射：列出的网络服务的连接只下发其规则集中的规则，
+// 以及黑名单等不属于任何规则集的规则；未列出的网络服务的连接下发全部规则集。
+// 列出的规则集中有任何一个在当前策略中不存在时，该网络服务的连接拒绝全部流量。
+func WithServiceRuleSets(services map[string][]string) Option {
+	return func(a *aclServer) {
+		a.serviceRuleSets = services
+	}
+}
+
+// WithEmergencyListener 订阅紧急模式切换，fn 在切换完成后调用（如更新注册的 NSE 标签）
+//
+// fn 在切换的调用方中同步调用，但不持有切换锁；连续切换时 fn 可能与之后的切换并发执行，
+// 需要当前模式时应读取 Controller.EmergencyStatus。fn 不应阻塞，耗时的操作（如访问注册中心）应异步执行。
+func WithEmergencyListener(fn func(ctx context.Context, mode EmergencyMode)) Option {
+	return func(a *aclServer) {
+		a.emergency.listeners = append(a.emergency.listeners, fn)
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/govpp/binapi/acl_types"
+	"github.com/networkservicemesh/govpp/binapi/ip_types"
+	"github.com/pkg/errors"
+)
+
+// Policy 防火墙策略，组合多个来源的规则
+//
+// 功能说明:
+//   - 规则按来源分层，Rules 按优先级返回合并后的规则：
+//     黑名单拒绝规则在前，其后是尚未到期的临时规则，配置的规则集（按顺序展开，见 Flatten）在最后
+//   - 任一来源变化时通知订阅者（ACL 服务器据此更新已有连接的 ACL）
+//   - 临时规则到期时由定时器自动删除（见 scheduleExpiry）
+type Policy struct {
+	mu        sync.RWMutex
+	blocklist []Rule
+	temporary []TemporaryRule
+	sets      []RuleSet
+	expiry    *time.Timer
+	listeners []func(ctx context.Context)
+}
+
+// ConfiguredRules 可在运行时修改的规则：配置的规则集和临时规则
+type ConfiguredRules struct {
+	RuleSets  []RuleSet
+	Temporary []TemporaryRule
+}
+
+// NewPolicy 创建以 sets 为配置规则集的策略
+func NewPolicy(sets []RuleSet) *Policy {
+	return &Policy{sets: sets}
+}
+
+// Rules 返回合并后的规则列表
+func (p *Policy) Rules() []Rule {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	temporary := flattenTemporary(p.temporary, p.sets, time.Now())
+	rules := Flatten(p.sets)
+	rv := make([]Rule, 0, len(p.blocklist)+len(temporary)+len(rules))
+	rv = append(rv, p.blocklist...)
+	rv = append(rv, temporary...)
+	return append(rv, rules...)
+}
+
+// configuredRules 返回尚未到期的临时规则和配置的规则集展开后的规则，不含黑名单规则
+func (p *Policy) configuredRules() []Rule {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append(flattenTemporary(p.temporary, p.sets, time.Now()), Flatten(p.sets)...)
+}
+
+// RuleSets 返回配置的规则集
+func (p *Policy) RuleSets() []RuleSet {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return cloneRuleSets(p.sets)
+}
+
+// TemporaryRules 返回尚未删除的临时规则
+func (p *Policy) TemporaryRules() []TemporaryRule {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]TemporaryRule{}, p.temporary...)
+}
+
+// SetTemporaryRules 替换临时规则，安排到期删除，并通知订阅者
+func (p *Policy) SetTemporaryRules(ctx context.Context, temporary []TemporaryRule) {
+	p.mu.Lock()
+	p.temporary = temporary
+	p.scheduleExpiry(ctx)
+	p.mu.Unlock()
+	p.notify(ctx)
+}
+
+// SetRuleSets 替换配置的规则集并通知订阅者
+func (p *Policy) SetRuleSets(ctx context.Context, sets []RuleSet) {
+	p.mu.Lock()
+	p.sets = sets
+	p.mu.Unlock()
+	p.notify(ctx)
+}
+
+// Update 原子地修改配置的规则集和临时规则
+//
+// fn 在持有策略锁时修改当前规则集和临时规则的副本，期间其他修改等待；
+// 修改后的内容通过校验（见 validateRules）后才会替换当前内容并通知订阅者。
+//
+// 参数:
+//   - ctx: 上下文
+//   - fn: 修改规则的函数
+//
+// 返回:
+//   - error: fn 返回的错误或校验错误，此时策略不变
+func (p *Policy) Update(ctx context.Context, fn func(rules *ConfiguredRules) error) error {
+	p.mu.Lock()
+	rules := &ConfiguredRules{
+		RuleSets:  cloneRuleSets(p.sets),
+		Temporary: append([]TemporaryRule{}, p.temporary...),
+	}
+	err := fn(rules)
+	if err == nil {
+		err = validateRules(rules)
+	}
+	if err != nil {
+		p.mu.Unlock()
+		return err
+	}
+	p.sets, p.temporary = rules.RuleSets, rules.Temporary
+	p.scheduleExpiry(ctx)
+	p.mu.Unlock()
+	p.notify(ctx)
+	return nil
+}
+
+// validateRules 校验规则集和临时规则（见 validateRuleSets、validateTemporary）
+func validateRules(rules *ConfiguredRules) error {
+	if err := validateRuleSets(rules.RuleSets); err != nil {
+		return err
+	}
+	return validateTemporary(rules.Temporary, rules.RuleSets)
+}
+
+// SetBlocklist 替换黑名单拒绝规则并通知订阅者
+func (p *Policy) SetBlocklist(ctx context.Context, rules []Rule) {
+	p.mu.Lock()
+	p.blocklist = rules
+	p.mu.Unlock()
+	p.notify(ctx)
+}
+
+// Subscribe 订阅策略变化，fn 在修改策略的 goroutine 中同步调用
+func (p *Policy) Subscribe(fn func(ctx context.Context)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.listeners = append(p.listeners, fn)
+}
+
+// notify 通知全部订阅者
+func (p *Policy) notify(ctx context.Context) {
+	p.mu.RLock()
+	listeners := append([]func(ctx context.Context){}, p.listeners...)
+	p.mu.RUnlock()
+	for _, fn := range listeners {
+		fn(ctx)
+	}
+}
+
+// DenyRule 创建拒绝发往 dst 的规则
+//
+// 出站镜像规则拒绝来自 dst 的流量，因此一条规则即可阻断与 dst 之间的双向流量。
+//
+// 参数:
+//   - name: 规则名称，同时作为规则的来源（RuleSet）
+//   - dst: 目标前缀
+//
+// 返回:
+//   - Rule: 拒绝规则，源前缀为同一地址族的任意地址
+//   - error: 前缀无效
+func DenyRule(name string, dst netip.Prefix) (Rule, error) {
+	dstPrefix, err := ip_types.ParsePrefix(dst.Masked().String())
+	if err != nil {
+		return Rule{}, errors.Wrapf(err, "无效的前缀 %v", dst)
+	}
+	return Rule{
+		Name:    name,
+		RuleSet: name,
+		ACLRule: acl_types.ACLRule{
+			IsPermit:  acl_types.ACL_ACTION_API_DENY,
+			SrcPrefix: anyPrefix(dstPrefix.Address.Af),
+			DstPrefix: dstPrefix,
+		},
+	}, nil
+}
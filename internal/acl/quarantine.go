@@ -0,0 +1,186 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+	"sync"
+
+	"github.com/networkservicemesh/govpp/binapi/acl_types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
+)
+
+// quarantineRuleSet 默认隔离规则（拒绝全部流量）的来源名称
+const quarantineRuleSet = "quarantine"
+
+var (
+	quarantinedMetricOnce sync.Once
+	quarantinedMetric     metric.Int64UpDownCounter
+)
+
+// addQuarantined 调整隔离连接数指标，OpenTelemetry 未启用时不记录
+func addQuarantined(ctx context.Context, delta int64) {
+	quarantinedMetricOnce.Do(func() {
+		if !opentelemetry.IsEnabled() {
+			return
+		}
+		counter, err := otel.Meter("").Int64UpDownCounter("acl_quarantined_connections",
+			metric.WithDescription("Number of connections isolated by the quarantine ACLs"))
+		if err != nil {
+			log.FromContext(ctx).WithField("acl", "metrics").Errorf("创建指标失败: %v", err)
+			return
+		}
+		quarantinedMetric = counter
+	})
+	if quarantinedMetric != nil {
+		quarantinedMetric.Add(ctx, delta)
+	}
+}
+
+// allServices 表示不按网络服务过滤规则（见 rules）
+const allServices = ""
+
+// missingRuleSetName 网络服务选择的规则集不存在时，该网络服务的连接使用的拒绝全部流量规则的来源名称
+const missingRuleSetName = "missing-rule-set"
+
+// rules 返回网络服务 service 的普通连接使用的规则，即策略中除隔离规则集和未被该网络服务选择的规则集以外的规则
+// （见 WithServiceRuleSets）；service 为 allServices 时不按网络服务过滤
+//
+// 网络服务选择的规则集在当前策略中不存在时（配置错误，或远程策略、管理接口删除了该规则集），
+// 该网络服务的连接拒绝全部流量，而不是因为没有规则而不下发 ACL。
+func (a *aclServer) rules(service string) []Rule {
+	rules := a.policy.Rules()
+	excluded, missing := a.selectRuleSets(service)
+	if len(missing) > 0 {
+		return matchAllRules(missingRuleSetName, acl_types.ACL_ACTION_API_DENY)
+	}
+	if a.quarantineRuleSet == "" && len(excluded) == 0 {
+		return rules
+	}
+	rv := rules[:0]
+	for i := range rules {
+		if rules[i].RuleSet != a.quarantineRuleSet && !excluded[rules[i].RuleSet] {
+			rv = append(rv, rules[i])
+		}
+	}
+	return rv
+}
+
+// declaredRules 返回需要登记到未命中规则报告的规则（见 RuleCounters.declare）
+//
+// 只包含配置的规则集和临时规则；隔离规则集只对隔离的连接生效，黑名单、防伪造、紧急模式、
+// 隔离和规则集缺失时的全匹配规则都是自动生成的，均不登记。
+func (a *aclServer) declaredRules() []Rule {
+	rules := a.policy.configuredRules()
+	rv := rules[:0]
+	for i := range rules {
+		if a.quarantineRuleSet == "" || rules[i].RuleSet != a.quarantineRuleSet {
+			rv = append(rv, rules[i])
+		}
+	}
+	return ingressRules(rv)
+}
+
+// selectRuleSets 返回网络服务 service 未选择的规则集名称，以及该网络服务选择了但当前策略中不存在的规则集名称；
+// 未配置该网络服务的规则集时均为空
+func (a *aclServer) selectRuleSets(service string) (excluded map[string]bool, missing []string) {
+	selected, ok := a.serviceRuleSets[service]
+	if !ok || service == allServices {
+		return nil, nil
+	}
+	excluded = make(map[string]bool)
+	for _, set := range a.policy.RuleSets() {
+		excluded[set.Name] = true
+	}
+	for _, name := range selected {
+		if !excluded[name] {
+			missing = append(missing, name)
+		}
+		delete(excluded, name)
+	}
+	return excluded, missing
+}
+
+// checkServiceRuleSets 对选择了当前策略中不存在的规则集的网络服务记录错误，这些网络服务的连接拒绝全部流量
+func (a *aclServer) checkServiceRuleSets(ctx context.Context) {
+	for service := range a.serviceRuleSets {
+		if _, missing := a.selectRuleSets(service); len(missing) > 0 {
+			log.FromContext(ctx).WithField("acl_server", "rule-sets").
+				Errorf("网络服务 %q 选择的规则集 %v 不存在，该网络服务的连接拒绝全部流量", service, missing)
+		}
+	}
+}
+
+// quarantineRules 返回隔离连接使用的规则
+//
+// 配置了隔离规则集（见 WithQuarantineRuleSet）且其中有规则时使用该规则集，否则拒绝全部 IPv4 和 IPv6 流量。
+// 出站方向由镜像规则同样拒绝（见 egressRules）。
+func (a *aclServer) quarantineRules(ctx context.Context) []Rule {
+	if a.quarantineRuleSet != "" {
+		for _, set := range a.policy.RuleSets() {
+			if set.Name == a.quarantineRuleSet && len(set.Rules) > 0 {
+				return Flatten([]RuleSet{set})
+			}
+		}
+		log.FromContext(ctx).WithField("acl_server", "quarantine").
+			Warnf("隔离规则集 %q 不存在或没有规则，隔离连接拒绝全部流量", a.quarantineRuleSet)
+	}
+	return matchAllRules(quarantineRuleSet, acl_types.ACL_ACTION_API_DENY)
+}
+
+// setQuarantine 隔离或解除隔离连接，按新的规则原地替换连接的 ACL，调用方需持有 st.mu
+//
+// 下发失败时连接的隔离状态保持不变并返回错误。
+//
+// 参数:
+//   - ctx: 上下文
+//   - connID: 连接 ID
+//   - st: 连接 ACL 状态
+//   - quarantined: true 为隔离，false 为解除隔离
+//
+// 返回:
+//   - error: 错误信息
+func (a *aclServer) setQuarantine(ctx context.Context, connID string, st *connState, quarantined bool) error {
+	if st.quarantined == quarantined {
+		return nil
+	}
+	st.quarantined = quarantined
+	err := a.retryPolicy.retry(ctx, connID, func(ctx context.Context) error {
+		return a.apply(ctx, connID, st)
+	})
+	if err != nil {
+		st.quarantined = !quarantined
+		return err
+	}
+
+	logger := log.FromContext(ctx).WithField("acl_server", "quarantine").WithField("connection", connID)
+	if quarantined {
+		addQuarantined(ctx, 1)
+		logger.Warn("连接已隔离")
+	} else {
+		addQuarantined(ctx, -1)
+		logger.Info("连接已解除隔离")
+	}
+	return nil
+}
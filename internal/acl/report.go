@@ -0,0 +1,119 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"sort"
+	"time"
+)
+
+// ruleKey 配置规则的标识：所属规则集和规则名称
+//
+// 规则名称只在规则集内唯一，不同规则集中的同名规则分别统计。
+type ruleKey struct {
+	ruleSet string
+	name    string
+}
+
+// keyOf 返回规则的标识
+func keyOf(r *Rule) ruleKey {
+	return ruleKey{ruleSet: r.RuleSet, name: r.Name}
+}
+
+// ruleActivity 单条配置规则的命中活动
+//
+// 按规则集和规则名称聚合所有连接、所有方向的命中，连接关闭后仍然保留，
+// 因此报告覆盖整个进程生命周期而不仅是当前活动的连接。
+// 命中按规则标识而不是在 VPP ACL 中的位置归属，防伪造规则和模板展开不影响统计。
+type ruleActivity struct {
+	order   int
+	action  string
+	since   time.Time
+	lastHit time.Time
+}
+
+// DeadRule 在时间窗口内没有命中任何流量的规则
+type DeadRule struct {
+	Rule          string     `json:"rule"`
+	RuleSet       string     `json:"ruleSet"`
+	Action        string     `json:"action"`
+	ObservedSince time.Time  `json:"observedSince"`
+	LastHit       *time.Time `json:"lastHit,omitempty"`
+	// Partial 为 true 表示观测时长短于时间窗口，结论仅供参考
+	Partial bool `json:"partial"`
+}
+
+// DeadRuleReport 未命中规则报告
+type DeadRuleReport struct {
+	Window      string     `json:"window"`
+	GeneratedAt time.Time  `json:"generatedAt"`
+	Rules       []DeadRule `json:"rules"`
+}
+
+// DeadRules 生成时间窗口内未命中任何流量的规则报告
+//
+// 功能说明:
+//   - 只包含配置的规则集和临时规则中的规则，黑名单、防伪造、紧急模式等自动生成的规则不在报告中
+//   - 最后一次命中早于 now-window（或从未命中）的规则视为未命中
+//   - 观测时长不足 window 的规则标记为 Partial
+//
+// 参数:
+//   - window: 时间窗口
+//
+// 返回:
+//   - *DeadRuleReport: 按规则在策略中的顺序排序的报告
+func (c *RuleCounters) DeadRules(window time.Duration) *DeadRuleReport {
+	now := time.Now()
+	cutoff := now.Add(-window)
+
+	report := &DeadRuleReport{
+		Window:      window.String(),
+		GeneratedAt: now,
+		Rules:       []DeadRule{},
+	}
+
+	c.mu.RLock()
+	order := make(map[ruleKey]int, len(c.activity))
+	for key, act := range c.activity {
+		if act.lastHit.After(cutoff) {
+			continue
+		}
+		rule := DeadRule{
+			Rule:          key.name,
+			RuleSet:       key.ruleSet,
+			Action:        act.action,
+			ObservedSince: act.since,
+			Partial:       act.since.After(cutoff),
+		}
+		if !act.lastHit.IsZero() {
+			lastHit := act.lastHit
+			rule.LastHit = &lastHit
+		}
+		order[key] = act.order
+		report.Rules = append(report.Rules, rule)
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(report.Rules, func(i, j int) bool {
+		return order[ruleKey{report.Rules[i].RuleSet, report.Rules[i].Rule}] <
+			order[ruleKey{report.Rules[j].RuleSet, report.Rules[j].Rule}]
+	})
+	return report
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.fd.io/govpp/api"
+	"go.fd.io/govpp/core"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// RetryPolicy ACL 编程失败时的重试策略
+//
+// 字段说明:
+//   - MaxAttempts: 最大尝试次数（含首次），小于等于 1 表示不重试
+//   - Backoff: 首次重试前的等待时间，之后每次翻倍
+//   - MaxBackoff: 等待时间上限，为 0 表示不设上限
+//   - CallTimeout: 单次 VPP API 调用的超时上限，为 0 表示只受请求上下文限制
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	CallTimeout time.Duration
+}
+
+// callTimeoutKey 上下文中单次 VPP API 调用超时的键
+type callTimeoutKey struct{}
+
+// withCallTimeout 在上下文中设置单次 VPP API 调用的超时
+func withCallTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, timeout)
+}
+
+// callContext 返回单次 VPP API 调用使用的上下文，超时由 withCallTimeout 设置
+func callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if timeout, ok := ctx.Value(callTimeoutKey{}).(time.Duration); ok && timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() {}
+}
+
+// attemptTimeout 计算本次尝试中单次 VPP API 调用的超时
+//
+// 请求上下文带有截止时间时，把剩余时间平均分给剩余的尝试次数，
+// 保证所有重试都能在请求截止前完成；结果不超过 CallTimeout。
+func (p *RetryPolicy) attemptTimeout(ctx context.Context, attemptsLeft int) time.Duration {
+	timeout := p.CallTimeout
+	if deadline, ok := ctx.Deadline(); ok && attemptsLeft > 0 {
+		share := time.Until(deadline) / time.Duration(attemptsLeft)
+		if timeout <= 0 || share < timeout {
+			timeout = share
+		}
+	}
+	return timeout
+}
+
+// backoff 返回第 attempt 次失败后的等待时间
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return d
+}
+
+// retry 按重试策略执行 fn，直到成功、遇到不可重试的错误或尝试次数用尽
+//
+// 参数:
+//   - ctx: 请求上下文
+//   - connID: 连接 ID（用于日志）
+//   - fn: 一次完整的 ACL 编程尝试，失败时必须已经回滚自己创建的 ACL
+//
+// 返回:
+//   - error: 最后一次尝试的错误
+func (p *RetryPolicy) retry(ctx context.Context, connID string, fn func(ctx context.Context) error) error {
+	logger := log.FromContext(ctx).WithField("acl_server", "retry").WithField("connection", connID)
+
+	maxAttempts := p.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		err := fn(withCallTimeout(ctx, p.attemptTimeout(ctx, maxAttempts-attempt+1)))
+		if err == nil {
+			return nil
+		}
+		if attempt >= maxAttempts || ctx.Err() != nil || !isRetryable(err) {
+			return err
+		}
+
+		wait := p.backoff(attempt)
+		logger.Warnf("ACL 编程失败（第 %d/%d 次），%v 后重试: %v", attempt, maxAttempts, wait, err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// isRetryable 判断 VPP API 调用错误是否为可重试的瞬时错误
+//
+// 技术细节:
+//   - VPP 返回的 retval 中，系统调用错误、响应未就绪和未连接视为瞬时错误，
+//     其余（如 INVALID_VALUE、NO_SUCH_ENTRY）说明请求本身有问题，不再重试
+//   - govpp 未连接、等待应答超时，以及单次调用超时（请求上下文仍然有效）视为瞬时错误
+func isRetryable(err error) bool {
+	var vppErr api.VPPApiError
+	if errors.As(err, &vppErr) {
+		switch {
+		case vppErr <= api.SYSCALL_ERROR_1 && vppErr >= api.SYSCALL_ERROR_10,
+			vppErr == api.RESPONSE_NOT_READY,
+			vppErr == api.NOT_CONNECTED:
+			return true
+		default:
+			return false
+		}
+	}
+	return errors.Is(err, core.ErrNotConnected) ||
+		errors.Is(err, core.ErrReplyTimeout) ||
+		errors.Is(err, core.ErrProbeTimeout) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
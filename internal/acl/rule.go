@@ -0,0 +1,168 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"github.com/networkservicemesh/govpp/binapi/acl_types"
+	"gopkg.in/yaml.v2"
+)
+
+// Rule 带名称的 ACL 规则
+//
+// 名称取自配置文件中的键（如 "allow tcp5201"），用于在指标和报告中标识规则；
+// 其余字段以内联方式复用 acl_types.ACLRule，保持原有配置文件格式不变。
+//
+// Stateful 为 true 的允许规则以 permit+reflect 下发到入站 ACL，由 VPP ACL 插件
+// 为发起方建立会话并放行回程流量，不再生成出站方向的镜像规则。
+//
+// TCPFlags 为命名的 TCP 标志条件（如 syn、!ack、established），解析时转换为
+// TCPFlagsMask 和 TCPFlagsValue。
+//
+// RuleSet 和 FailureMode 取自规则所属的规则集（见 RuleSet），不在配置文件中逐条配置；
+// 黑名单、防伪造等自动生成的规则以来源名称作为 RuleSet。
+//
+// srcprefix/dstprefix 中可以使用 ${client.ip}、${endpoint.ip} 占位符，
+// 保存在 SrcPrefixTemplate/DstPrefixTemplate 中，建立连接时按连接地址替换。
+type Rule struct {
+	Name              string      `yaml:"-"`
+	RuleSet           string      `yaml:"-"`
+	Stateful          bool        `yaml:"stateful"`
+	FailureMode       FailureMode `yaml:"-"`
+	TCPFlags          []string    `yaml:"tcpflags"`
+	SrcPrefixTemplate string      `yaml:"-"`
+	DstPrefixTemplate string      `yaml:"-"`
+	acl_types.ACLRule `yaml:",inline"`
+
+	// source 配置中规则的原始字段，运行时修改规则后据此写回状态文件（见 SaveState）
+	source yaml.MapSlice
+}
+
+// UnmarshalYAML 解析规则，取出前缀模板，并将命名的 TCP 标志条件转换为掩码和值
+func (r *Rule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var fields yaml.MapSlice
+	if err := unmarshal(&fields); err != nil {
+		return err
+	}
+	rest, srcTemplate, dstTemplate, err := extractTemplates(fields)
+	if err != nil {
+		return err
+	}
+	raw, err := yaml.Marshal(rest)
+	if err != nil {
+		return err
+	}
+
+	type plain Rule
+	if err := yaml.Unmarshal(raw, (*plain)(r)); err != nil {
+		return err
+	}
+	r.SrcPrefixTemplate, r.DstPrefixTemplate = srcTemplate, dstTemplate
+	r.source = fields
+	return r.applyTCPFlags()
+}
+
+// reflective 判断规则是否以 permit+reflect 方式下发
+func (r *Rule) reflective() bool {
+	return r.IsPermit == acl_types.ACL_ACTION_API_PERMIT_REFLECT ||
+		(r.Stateful && r.IsPermit == acl_types.ACL_ACTION_API_PERMIT)
+}
+
+// ingressRules 构造入站 ACL 的规则列表
+//
+// 有状态的允许规则转换为 permit+reflect，其余规则保持不变。
+func ingressRules(rules []Rule) []Rule {
+	rv := make([]Rule, 0, len(rules))
+	for i := range rules {
+		r := rules[i]
+		if r.reflective() {
+			r.IsPermit = acl_types.ACL_ACTION_API_PERMIT_REFLECT
+		}
+		rv = append(rv, r)
+	}
+	return rv
+}
+
+// egressRules 构造出站 ACL 的规则列表
+//
+// 功能说明:
+//   - 无状态规则镜像到出站方向，允许或拒绝反方向的流量
+//   - 有状态的允许规则由入站 ACL 建立的会话放行回程流量，不再镜像
+//
+// 技术细节（VPP ACL 规则默认是入站方向，出站规则需要反转匹配条件）:
+//   - 交换源/目标 IP 前缀 (SrcPrefix ↔ DstPrefix)
+//   - 交换源/目标端口 (SrcportOrIcmptypeFirst ↔ DstportOrIcmpcodeFirst)
+//   - 交换源/目标端口范围 (SrcportOrIcmptypeLast ↔ DstportOrIcmpcodeLast)
+//   - 匹配 syn,!ack 的规则改为匹配 syn,ack（见 mirrorTCPFlags）
+func egressRules(rules []Rule) []Rule {
+	rv := make([]Rule, 0, len(rules))
+	for i := range rules {
+		if rules[i].reflective() {
+			continue
+		}
+		r := rules[i]
+		// 交换源/目标 IP 前缀
+		r.SrcPrefix, r.DstPrefix = r.DstPrefix, r.SrcPrefix
+
+		// 交换源/目标端口（或 ICMP 类型/代码）
+		r.SrcportOrIcmptypeFirst, r.DstportOrIcmpcodeFirst = r.DstportOrIcmpcodeFirst, r.SrcportOrIcmptypeFirst
+		r.SrcportOrIcmptypeLast, r.DstportOrIcmpcodeLast = r.DstportOrIcmpcodeLast, r.SrcportOrIcmptypeLast
+
+		// 发起连接的 SYN 在反方向对应 SYN-ACK
+		r.TCPFlagsValue = mirrorTCPFlags(r.TCPFlagsMask, r.TCPFlagsValue)
+		rv = append(rv, r)
+	}
+	return rv
+}
+
+// statefulRuleName 规则全部为有状态规则时，出站方向自动生成的拒绝全部流量规则的名称前缀和来源（见 connectionRules）
+const statefulRuleName = "stateful"
+
+// hasStateful 判断规则列表中是否有以 permit+reflect 方式下发的规则
+func hasStateful(rules []Rule) bool {
+	for i := range rules {
+		if rules[i].reflective() {
+			return true
+		}
+	}
+	return false
+}
+
+// vppRules 提取规则列表中的 VPP ACL 规则，顺序保持不变
+func vppRules(rules []Rule) []acl_types.ACLRule {
+	rv := make([]acl_types.ACLRule, 0, len(rules))
+	for i := range rules {
+		rv = append(rv, rules[i].ACLRule)
+	}
+	return rv
+}
+
+// actionName 返回规则动作的简短名称，用作指标标签
+func actionName(action acl_types.ACLAction) string {
+	switch action {
+	case acl_types.ACL_ACTION_API_DENY:
+		return "deny"
+	case acl_types.ACL_ACTION_API_PERMIT:
+		return "permit"
+	case acl_types.ACL_ACTION_API_PERMIT_REFLECT:
+		return "permit+reflect"
+	default:
+		return action.String()
+	}
+}
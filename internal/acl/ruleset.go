@@ -0,0 +1,228 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultRuleSet 旧版配置格式（规则名称到规则的映射）中全部规则所属的规则集名称
+const DefaultRuleSet = "default"
+
+// ruleSetsKey 新版配置格式的顶层键
+const ruleSetsKey = "ruleSets"
+
+// RuleSet 一组按顺序匹配的规则
+//
+// Stateful 为 true 时，集合中的全部允许规则都按有状态方式下发（见 Rule.Stateful）。
+// FailureMode 决定 ACL 下发失败时连接的处理方式（见 FailureMode），未配置时为 FailureClosed。
+type RuleSet struct {
+	Name        string
+	Stateful    bool
+	FailureMode FailureMode
+	Rules       []Rule
+}
+
+// ruleSetConfig 配置文件中一个规则集的格式，rules 保持文件中的顺序
+type ruleSetConfig struct {
+	Name        string        `yaml:"name"`
+	Stateful    bool          `yaml:"stateful"`
+	FailureMode FailureMode   `yaml:"failureMode"`
+	Rules       yaml.MapSlice `yaml:"rules"`
+}
+
+// ParseRuleSets 解析 ACL 配置文件
+//
+// 功能说明:
+//   - 新版格式: 顶层 ruleSets 列表，每个规则集包含 name、stateful 和 rules
+//   - 旧版格式: 顶层为规则名称到规则的映射，全部规则归入 DefaultRuleSet
+//   - 两种格式中的规则都按文件中的顺序返回
+//
+// 参数:
+//   - raw: 配置文件内容
+//
+// 返回:
+//   - []RuleSet: 规则集列表
+//   - error: 格式错误或规则名称重复
+func ParseRuleSets(raw []byte) ([]RuleSet, error) {
+	var top yaml.MapSlice
+	if err := yaml.Unmarshal(raw, &top); err != nil {
+		return nil, errors.Wrap(err, "解析 ACL 配置失败")
+	}
+
+	newFormat := false
+	for _, item := range top {
+		newFormat = newFormat || item.Key == ruleSetsKey
+	}
+
+	var sets []RuleSet
+	if newFormat {
+		if len(top) != 1 {
+			return nil, errors.Errorf("使用 %s 格式时不能同时在顶层定义规则", ruleSetsKey)
+		}
+		var cfg struct {
+			RuleSets []ruleSetConfig `yaml:"ruleSets"`
+		}
+		if err := yaml.Unmarshal(raw, &cfg); err != nil {
+			return nil, errors.Wrap(err, "解析 ACL 规则集失败")
+		}
+		var err error
+		if sets, err = buildRuleSets(cfg.RuleSets); err != nil {
+			return nil, err
+		}
+	} else if len(top) > 0 {
+		rules, err := parseRules(top)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, RuleSet{Name: DefaultRuleSet, FailureMode: FailureClosed, Rules: rules})
+	}
+
+	if err := validateRuleSets(sets); err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+// buildRuleSets 按顺序解析配置中的规则集，未配置 failureMode 时为 FailureClosed
+func buildRuleSets(cfgs []ruleSetConfig) ([]RuleSet, error) {
+	sets := make([]RuleSet, 0, len(cfgs))
+	for _, setCfg := range cfgs {
+		rules, err := parseRules(setCfg.Rules)
+		if err != nil {
+			return nil, errors.Wrapf(err, "规则集 %q", setCfg.Name)
+		}
+		mode := setCfg.FailureMode
+		if mode == "" {
+			mode = FailureClosed
+		}
+		sets = append(sets, RuleSet{Name: setCfg.Name, Stateful: setCfg.Stateful, FailureMode: mode, Rules: rules})
+	}
+	return sets, nil
+}
+
+// ruleSetConfigs 将规则集转换回配置格式，规则按其原始字段写出
+func ruleSetConfigs(sets []RuleSet) []ruleSetConfig {
+	cfgs := make([]ruleSetConfig, 0, len(sets))
+	for _, set := range sets {
+		rules := make(yaml.MapSlice, 0, len(set.Rules))
+		for i := range set.Rules {
+			rules = append(rules, yaml.MapItem{Key: set.Rules[i].Name, Value: set.Rules[i].source})
+		}
+		cfgs = append(cfgs, ruleSetConfig{Name: set.Name, Stateful: set.Stateful, FailureMode: set.FailureMode, Rules: rules})
+	}
+	return cfgs
+}
+
+// validateRuleSets 校验规则集：规则集名称不能为空或重复，规则名称在所有规则集中唯一
+func validateRuleSets(sets []RuleSet) error {
+	setNames := make(map[string]bool)
+	seen := make(map[string]string)
+	for _, set := range sets {
+		if set.Name == "" {
+			return errors.New("规则集缺少名称")
+		}
+		if setNames[set.Name] {
+			return errors.Errorf("规则集 %q 重复", set.Name)
+		}
+		setNames[set.Name] = true
+		for i := range set.Rules {
+			if set.Rules[i].Name == "" {
+				return errors.Errorf("规则集 %q 中有规则缺少名称", set.Name)
+			}
+			if other, ok := seen[set.Rules[i].Name]; ok {
+				return errors.Errorf("规则名称 %q 在规则集 %q 和 %q 中重复", set.Rules[i].Name, other, set.Name)
+			}
+			seen[set.Rules[i].Name] = set.Name
+		}
+	}
+	return nil
+}
+
+// cloneRuleSets 复制规则集列表，修改副本中的规则列表不影响原列表
+func cloneRuleSets(sets []RuleSet) []RuleSet {
+	rv := make([]RuleSet, 0, len(sets))
+	for _, set := range sets {
+		set.Rules = append([]Rule{}, set.Rules...)
+		rv = append(rv, set)
+	}
+	return rv
+}
+
+// parseRules 按顺序解析规则名称到规则的映射
+func parseRules(items yaml.MapSlice) ([]Rule, error) {
+	rules := make([]Rule, 0, len(items))
+	for _, item := range items {
+		name := fmt.Sprint(item.Key)
+		raw, err := yaml.Marshal(item.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "规则 %q", name)
+		}
+		var rule Rule
+		if err := yaml.Unmarshal(raw, &rule); err != nil {
+			return nil, errors.Wrapf(err, "规则 %q", name)
+		}
+		rule.Name = name
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ParseRule 解析单条规则，格式与配置文件中一条规则的内容相同（YAML 或 JSON）
+//
+// 参数:
+//   - name: 规则名称
+//   - raw: 规则内容
+//
+// 返回:
+//   - Rule: 解析后的规则
+//   - error: 名称为空或规则格式错误
+func ParseRule(name string, raw []byte) (Rule, error) {
+	if name == "" {
+		return Rule{}, errors.New("规则缺少名称")
+	}
+	var rule Rule
+	if err := yaml.Unmarshal(raw, &rule); err != nil {
+		return Rule{}, errors.Wrapf(err, "规则 %q", name)
+	}
+	if rule.source == nil {
+		return Rule{}, errors.Errorf("规则 %q 内容为空", name)
+	}
+	rule.Name = name
+	return rule, nil
+}
+
+// Flatten 按顺序展开规则集中的规则，规则集的名称、Stateful 和 FailureMode 设置传递给其中的每条规则
+func Flatten(sets []RuleSet) []Rule {
+	var rv []Rule
+	for _, set := range sets {
+		for i := range set.Rules {
+			r := set.Rules[i]
+			r.Stateful = r.Stateful || set.Stateful
+			r.RuleSet = set.Name
+			r.FailureMode = set.FailureMode
+			rv = append(rv, r)
+		}
+	}
+	return rv
+}
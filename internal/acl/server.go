@@ -28,19 +28,17 @@ package acl
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	"github.com/edwarnicke/genericsync"
 	"github.com/golang/protobuf/ptypes/empty"
-	"github.com/networkservicemesh/govpp/binapi/acl"
-	"github.com/networkservicemesh/govpp/binapi/acl_types"
 	"github.com/pkg/errors"
 	"go.fd.io/govpp/api"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk-vpp/pkg/tools/ifindex"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
-	"github.com/networkservicemesh/sdk/pkg/tools/log"
 	"github.com/networkservicemesh/sdk/pkg/tools/postpone"
 )
 
@@ -48,44 +46,78 @@ import (
 // 负责管理 VPP ACL 规则的创建、应用和删除
 //
 // 字段说明:
-//   - vppConn: VPP API 连接，用于与 VPP 交互
-//   - aclRules: 预配置的 ACL 规则列表（从配置文件加载）
-//   - aclIndices: 连接 ID 到 ACL 索引的映射（线程安全）
+//   - backend: ACL 编程后端，默认直接调用 VPP 二进制 API
+//   - policy: 防火墙策略，提供当前规则列表，变化时更新已有连接
+//   - conns: 连接 ID 到连接 ACL 状态的映射（线程安全）
+//   - counters: 规则命中计数采集器（可选）
+//   - retryPolicy: ACL 编程失败时的重试策略
+//   - position: 本元素 ACL 在接口 ACL 列表中的位置
+//   - antiSpoof: 是否在入站 ACL 最前面加入拒绝伪造源地址的规则
+//   - chunkSize: 每个 ACL 的规则数上限，规则更多时切分为多个 ACL
+//   - degradedRetryInterval: fail-open 降级连接后台重试下发 ACL 的间隔
+//   - quarantineRuleSet: 隔离连接使用的规则集名称，为空时隔离连接拒绝全部流量
+//   - emergency: 全局紧急模式状态（见 setEmergency）
+//   - serviceRuleSets: 网络服务名称到其连接下发的规则集名称的映射，未列出的网络服务下发全部规则集
 type aclServer struct {
-	vppConn    api.Connection                      // VPP API 连接
-	aclRules   []acl_types.ACLRule                 // 预配置的 ACL 规则列表
-	aclIndices genericsync.Map[string, []uint32]   // 连接 ID -> ACL 索引映射（线程安全）
+	backend     ACLBackend                          // ACL 编程后端
+	policy      *Policy                             // 防火墙策略
+	conns       genericsync.Map[string, *connState] // 连接 ID -> 连接 ACL 状态（线程安全）
+	counters    *RuleCounters                       // 规则命中计数采集器（可选）
+	retryPolicy RetryPolicy                         // ACL 编程重试策略
+	position    Position                            // ACL 在接口 ACL 列表中的位置
+	antiSpoof   bool                                // 是否自动生成防伪造规则
+	chunkSize   int                                 // 每个 ACL 的规则数上限
+
+	degradedRetryInterval time.Duration // 降级连接重试间隔
+	quarantineRuleSet     string        // 隔离规则集名称
+	emergency             emergencyState
+	serviceRuleSets       map[string][]string // 网络服务 -> 规则集名称
+	reconciles            reconciles          // 进行中的全部连接 ACL 重新下发
 }
 
 // NewServer 创建 ACL NetworkServiceServer 链式元素
 //
 // 功能说明:
 //   - 创建一个 ACL 服务器，用于在 VPP 接口上应用 ACL 规则
-//   - 作为 NSM 链式处理的一个环节，接收请求并��递给下一个处理器
+//   - 作为 NSM 链式处理的一个环节，接收请求并传递给下一个处理器
+//   - 订阅策略变化，策略更新后重新下发已有连接的 ACL
 //
 // 参数:
 //   - vppConn: VPP API 连接
-//   - aclrules: 要应用的 ACL 规则列表（通常从配置文件加载）
+//   - policy: 防火墙策略（配置规则和黑名单等规则来源）
+//   - options: 可选配置项（如 WithRuleCounters、WithBackend）
 //
 // 返回:
 //   - networkservice.NetworkServiceServer: NSM 网络服务服务器接口实现
 //
 // 使用示例:
-//   aclServer := acl.NewServer(vppConn, config.ACLConfig)
-func NewServer(vppConn api.Connection, aclrules []acl_types.ACLRule) networkservice.NetworkServiceServer {
-	return &aclServer{
-		vppConn:  vppConn,
-		aclRules: aclrules,
+//   aclServer := acl.NewServer(vppConn, acl.NewPolicy(config.ACLRuleSets), acl.WithRuleCounters(counters))
+func NewServer(vppConn api.Connection, policy *Policy, options ...Option) networkservice.NetworkServiceServer {
+	a := &aclServer{
+		backend:               NewVPPBackend(vppConn),
+		policy:                policy,
+		position:              PositionPrepend,
+		degradedRetryInterval: defaultDegradedRetryInterval,
 	}
+	a.emergency.status.Mode = EmergencyOff
+	for _, opt := range options {
+		opt(a)
+	}
+	if a.counters != nil {
+		a.counters.declare(a.declaredRules())
+	}
+	a.policy.Subscribe(a.reapply)
+	return a
 }
 
 // Request 处理网络服务请求
 //
 // 功能说明:
 //   1. 调用链中下一个服务器处理请求
-//   2. 检查此连接是否已应用 ACL 规则
-//   3. 如果未应用且配置了 ACL 规则，则创建并应用规则
-//   4. 如果创建失败，自动清理连接并返回错误
+//   2. 检查此连接是否已登记
+//   3. 如果未登记，则登记连接并按当前策略创建并应用规则
+//   4. 如果创建失败：规则的失败模式为 closed 时自动清理连接并返回错误；
+//      为 open 时照常返回连接，标记为降级并在后台重试（见 degrade）
 //
 // 处理流程:
 //   Request → next.Server().Request() → 检查 ACL → 创建/跳过 → 返回连接
@@ -101,46 +133,75 @@ func (a *aclServer) Request(ctx context.Context, request *networkservice.Network
 	// 创建延迟清理上下文，用于失败时清理资源
 	postponeCtxFunc := postpone.ContextWithValues(ctx)
 
-	// 调用链中的��一个服务器
+	// 调用链中的下一个服务器
 	conn, err := next.Server(ctx).Request(ctx, request)
 	if err != nil {
 		return nil, err
 	}
 
 	// 检查是否已为此连接创建 ACL
-	_, loaded := a.aclIndices.Load(conn.GetId())
-	if !loaded && len(a.aclRules) > 0 {
-		// 创建 ACL 规则并应用到 VPP 接口
-		var indices []uint32
-		if indices, err = create(ctx, a.vppConn, fmt.Sprintf("%s-%s", aclTag, conn.GetId()), metadata.IsClient(a), a.aclRules); err != nil {
-			// 创建失败时，使用延迟上下文清理连接
-			closeCtx, cancelClose := postponeCtxFunc()
-			defer cancelClose()
-
-			if _, closeErr := a.Close(closeCtx, conn); closeErr != nil {
-				// 包装错误信息，同时报告 ACL 创建失败和连接关闭失败
-				err = errors.Wrapf(err, "连接关闭时发生错误: %s", closeErr.Error())
-			}
-
-			return nil, err
+	if _, loaded := a.conns.Load(conn.GetId()); loaded {
+		return conn, nil
+	}
+
+	// 获取软件接口索引
+	swIfIndex, ok := ifindex.Load(ctx, metadata.IsClient(a))
+	if !ok {
+		if !a.hasRules(conn.GetNetworkService()) {
+			return conn, nil
 		}
+		return nil, a.closeOnError(postponeCtxFunc, conn, errors.New("未找到软件接口索引 (swIfIndex)"))
+	}
 
-		// 存储 ACL 索引，用于后续清理
-		a.aclIndices.Store(conn.GetId(), indices)
+	// 先登记连接再下发规则：期间发生的策略变化会等待本次下发完成后再更新此连接
+	st := &connState{swIfIndex: swIfIndex, service: conn.GetNetworkService(), ipContext: conn.GetContext().GetIpContext(), path: pathNames(conn)}
+	st.mu.Lock()
+	if _, loaded := a.conns.LoadOrStore(conn.GetId(), st); loaded {
+		st.mu.Unlock()
+		return conn, nil
+	}
+
+	// 创建 ACL 规则并应用到 VPP 接口（瞬时错误按重试策略重试）
+	err = a.retryPolicy.retry(ctx, conn.GetId(), func(ctx context.Context) error {
+		return a.apply(ctx, conn.GetId(), st)
+	})
+	if err != nil && failureMode(a.rules(st.service)) == FailureOpen {
+		a.degrade(ctx, conn.GetId(), st, err)
+		err = nil
+	}
+	st.mu.Unlock()
+	if err != nil {
+		return nil, a.closeOnError(postponeCtxFunc, conn, err)
 	}
 
 	return conn, nil
 }
 
+// closeOnError 创建 ACL 失败时，使用延迟上下文关闭连接
+//
+// 返回:
+//   - error: 原错误；关闭连接也失败时，同时报告两个错误
+func (a *aclServer) closeOnError(postponeCtxFunc func() (context.Context, context.CancelFunc), conn *networkservice.Connection, err error) error {
+	closeCtx, cancelClose := postponeCtxFunc()
+	defer cancelClose()
+
+	if _, closeErr := a.Close(closeCtx, conn); closeErr != nil {
+		// 包装错误信息，同时报告 ACL 创建失败和连接关闭失败
+		err = errors.Wrapf(err, "连接关闭时发生错误: %s", closeErr.Error())
+	}
+	return err
+}
+
 // Close 关闭连接并清理 ACL 规则
 //
 // 功能说明:
-//   1. 从映射中加载并删除此连接的 ACL 索引
-//   2. 调用 VPP API 删除每个 ACL 规则
-//   3. 调用链中的下一个服务器继续关闭流程
+//   1. 从映射中加载并删除此连接的 ACL 状态
+//   2. 从接口 ACL 列表中移除本元素的 ACL，其他组件的 ACL 保持不变
+//   3. 调用 VPP API 删除每个 ACL 规则
+//   4. 调用链中的下一个服务器继续关闭流程
 //
 // 处理流程:
-//   Close → 加载 ACL 索引 → 删除 VPP ACL → next.Server().Close()
+//   Close → 加载 ACL 状态 → 解绑接口 → 删除 VPP ACL → next.Server().Close()
 //
 // 参数:
 //   - ctx: 上下文
@@ -150,16 +211,16 @@ func (a *aclServer) Request(ctx context.Context, request *networkservice.Network
 //   - *empty.Empty: 空响应
 //   - error: 错误信息
 func (a *aclServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
-	// 加载并删除此连接的 ACL 索引
-	indices, _ := a.aclIndices.LoadAndDelete(conn.GetId())
-
-	// 删除 VPP 中的每个 ACL 规则
-	for ind := range indices {
-		_, err := acl.NewServiceClient(a.vppConn).ACLDel(ctx, &acl.ACLDel{ACLIndex: uint32(ind)})
-		if err != nil {
-			// 删除失败只记录调试日志，不中断关闭流程
-			log.FromContext(ctx).Debug("ACL 服务器: 删除 ACL 规则失败")
+	// 加载并删除此连接的 ACL 状态
+	if st, ok := a.conns.LoadAndDelete(conn.GetId()); ok {
+		st.mu.Lock()
+		st.closed = true
+		a.recoverDegraded(ctx, conn.GetId(), st)
+		if st.quarantined {
+			addQuarantined(ctx, -1)
 		}
+		a.release(ctx, conn.GetId(), st)
+		st.mu.Unlock()
 	}
 
 	// 调用链中的下一个服务器
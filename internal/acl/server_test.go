@@ -0,0 +1,229 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/networkservicemesh/govpp/binapi/acl_types"
+	"github.com/pkg/errors"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+	"github.com/ifzzh/cmd-nse-template/internal/acl/acltest"
+)
+
+const (
+	allowIntranet = `
+allow 10.0.0.0/8:
+  srcprefix: 10.0.0.0/8
+  ispermit: 1
+`
+	allowIntranetAndDMZ = `
+allow 10.0.0.0/8:
+  srcprefix: 10.0.0.0/8
+  ispermit: 1
+allow 192.168.0.0/16:
+  srcprefix: 192.168.0.0/16
+  ispermit: 1
+`
+)
+
+// callsOf 返回 calls 中方法为 method 的调用
+func callsOf(calls []acltest.Call, method string) []acltest.Call {
+	var rv []acltest.Call
+	for i := range calls {
+		if calls[i].Method == method {
+			rv = append(rv, calls[i])
+		}
+	}
+	return rv
+}
+
+func TestRequestCreatesAndBindsACLs(t *testing.T) {
+	backend := acltest.NewBackend()
+	// 其他组件已绑定在接口上的 ACL
+	foreign, err := backend.Create(context.Background(), "other", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = backend.Bind(context.Background(), testSwIfIndex, []uint32{foreign}, nil); err != nil {
+		t.Fatal(err)
+	}
+	server := newTestServer(backend, acl.NewPolicy(parseRuleSets(t, allowIntranet)))
+
+	if _, err = server.Request(context.Background(), testRequest("conn-1")); err != nil {
+		t.Fatalf("Request 失败: %v", err)
+	}
+
+	created := callsOf(backend.Calls(), acltest.MethodCreate)[1:]
+	if len(created) != 2 {
+		t.Fatalf("创建了 %d 个 ACL，期望 2（入站和出站）", len(created))
+	}
+	for i := range created {
+		if created[i].Tag != "nsm-acl-from-config-conn-1" {
+			t.Errorf("ACL %d 的标签 = %q", created[i].Index, created[i].Tag)
+		}
+	}
+	ingress, egress := created[0].Index, created[1].Index
+	want := acltest.InterfaceACLs{Input: []uint32{ingress, foreign}, Output: []uint32{egress}}
+	if got := backend.Interface(testSwIfIndex); !reflect.DeepEqual(got, want) {
+		t.Fatalf("接口绑定 = %+v，期望 %+v（本元素的 ACL 在前，保留其他组件的 ACL）", got, want)
+	}
+
+	input, output := boundRules(t, backend)
+	if got := firstMatch(t, input[0], "10.0.0.2"); got != acl_types.ACL_ACTION_API_PERMIT {
+		t.Errorf("入站源地址 10.0.0.2 的动作 = %v，期望允许", got)
+	}
+	if len(output[0]) != 1 {
+		t.Errorf("出站规则数量 = %d，期望 1（镜像规则）", len(output[0]))
+	}
+}
+
+func TestPolicyChangeReplacesACLsInPlace(t *testing.T) {
+	backend := acltest.NewBackend()
+	policy := acl.NewPolicy(parseRuleSets(t, allowIntranet))
+	server := newTestServer(backend, policy)
+
+	if _, err := server.Request(context.Background(), testRequest("conn-1")); err != nil {
+		t.Fatalf("Request 失败: %v", err)
+	}
+	before := backend.Interface(testSwIfIndex)
+	callsBefore := len(backend.Calls())
+
+	policy.SetRuleSets(context.Background(), parseRuleSets(t, allowIntranetAndDMZ))
+
+	calls := backend.Calls()[callsBefore:]
+	if n := len(callsOf(calls, acltest.MethodCreate)) + len(callsOf(calls, acltest.MethodDelete)) + len(callsOf(calls, acltest.MethodBind)); n != 0 {
+		t.Fatalf("策略变化后有 %d 次创建、删除或绑定调用，期望只原地替换: %+v", n, calls)
+	}
+	replaced := callsOf(calls, acltest.MethodReplace)
+	if len(replaced) != 2 || replaced[0].Index != before.Input[0] || replaced[1].Index != before.Output[0] {
+		t.Fatalf("原地替换调用 = %+v，期望替换入站 ACL %d 和出站 ACL %d", replaced, before.Input[0], before.Output[0])
+	}
+	if got := backend.Interface(testSwIfIndex); !reflect.DeepEqual(got, before) {
+		t.Fatalf("接口绑定 = %+v，期望不变 %+v", got, before)
+	}
+	input, _ := boundRules(t, backend)
+	if got := firstMatch(t, input[0], "192.168.1.1"); got != acl_types.ACL_ACTION_API_PERMIT {
+		t.Errorf("源地址 192.168.1.1 的动作 = %v，期望允许（新规则已生效）", got)
+	}
+}
+
+func TestPolicyChangeRestoresRulesWhenEgressReplaceFails(t *testing.T) {
+	backend := acltest.NewBackend()
+	policy := acl.NewPolicy(parseRuleSets(t, allowIntranet))
+	server := newTestServer(backend, policy)
+
+	if _, err := server.Request(context.Background(), testRequest("conn-1")); err != nil {
+		t.Fatalf("Request 失败: %v", err)
+	}
+	before := backend.Interface(testSwIfIndex)
+	beforeACLs := backend.ACLs()
+
+	// 入站 ACL 替换成功，出站 ACL 替换失败
+	backend.InjectACLError(acltest.MethodReplace, before.Output[0], errors.New("injected"))
+	policy.SetRuleSets(context.Background(), parseRuleSets(t, allowIntranetAndDMZ))
+
+	if got := backend.Interface(testSwIfIndex); !reflect.DeepEqual(got, before) {
+		t.Fatalf("接口绑定 = %+v，期望不变 %+v", got, before)
+	}
+	if got := backend.ACLs(); !reflect.DeepEqual(got, beforeACLs) {
+		t.Fatalf("ACL = %+v，期望入站和出站都保持原有的规则 %+v", got, beforeACLs)
+	}
+	input, _ := boundRules(t, backend)
+	if got := firstMatch(t, input[0], "192.168.1.1"); got != acl_types.ACL_ACTION_API_DENY {
+		t.Errorf("源地址 192.168.1.1 的动作 = %v，期望拒绝（新规则未生效）", got)
+	}
+
+	// 之后的策略变化照常原地替换
+	policy.SetRuleSets(context.Background(), parseRuleSets(t, allowIntranetAndDMZ))
+	input, _ = boundRules(t, backend)
+	if got := firstMatch(t, input[0], "192.168.1.1"); got != acl_types.ACL_ACTION_API_PERMIT {
+		t.Errorf("源地址 192.168.1.1 的动作 = %v，期望允许", got)
+	}
+}
+
+func TestCloseUnbindsAndDeletesACLs(t *testing.T) {
+	backend := acltest.NewBackend()
+	server := newTestServer(backend, acl.NewPolicy(parseRuleSets(t, allowIntranet)))
+
+	conn, err := server.Request(context.Background(), testRequest("conn-1"))
+	if err != nil {
+		t.Fatalf("Request 失败: %v", err)
+	}
+	if _, err = server.Close(context.Background(), conn); err != nil {
+		t.Fatalf("Close 失败: %v", err)
+	}
+
+	if bound := backend.Interface(testSwIfIndex); len(bound.Input)+len(bound.Output) != 0 {
+		t.Fatalf("关闭后接口仍绑定 ACL: %+v", bound)
+	}
+	if acls := backend.ACLs(); len(acls) != 0 {
+		t.Fatalf("关闭后仍有 %d 个 ACL", len(acls))
+	}
+	if deleted := callsOf(backend.Calls(), acltest.MethodDelete); len(deleted) != 2 {
+		t.Fatalf("删除调用 = %+v，期望删除入站和出站 ACL", deleted)
+	}
+}
+
+func TestRequestRollsBackWhenBindFails(t *testing.T) {
+	backend := acltest.NewBackend()
+	server := newTestServer(backend, acl.NewPolicy(parseRuleSets(t, allowIntranet)))
+	backend.InjectError(acltest.MethodBind, errors.New("injected"))
+
+	if _, err := server.Request(context.Background(), testRequest("conn-1")); err == nil {
+		t.Fatal("Request 成功，期望返回绑定错误")
+	}
+
+	if acls := backend.ACLs(); len(acls) != 0 {
+		t.Fatalf("回滚后仍有 %d 个 ACL", len(acls))
+	}
+	if bound := backend.Interface(testSwIfIndex); len(bound.Input)+len(bound.Output) != 0 {
+		t.Fatalf("回滚后接口绑定了 ACL: %+v", bound)
+	}
+}
+
+func TestPolicyChangeKeepsACLsWhenRecreateFails(t *testing.T) {
+	backend := acltest.NewBackend()
+	policy := acl.NewPolicy(parseRuleSets(t, allowIntranet))
+	server := newTestServer(backend, policy, acl.WithChunkSize(1))
+
+	if _, err := server.Request(context.Background(), testRequest("conn-1")); err != nil {
+		t.Fatalf("Request 失败: %v", err)
+	}
+	before := backend.Interface(testSwIfIndex)
+	beforeACLs := backend.ACLs()
+
+	// 规则数增加后 ACL 数量变化，需要创建新的 ACL 并重新绑定；绑定失败时回滚新建的 ACL
+	backend.InjectError(acltest.MethodBind, errors.New("injected"))
+	policy.SetRuleSets(context.Background(), parseRuleSets(t, allowIntranetAndDMZ))
+
+	if got := backend.Interface(testSwIfIndex); !reflect.DeepEqual(got, before) {
+		t.Fatalf("接口绑定 = %+v，期望保持原有的 ACL %+v", got, before)
+	}
+	if got := backend.ACLs(); !reflect.DeepEqual(got, beforeACLs) {
+		t.Fatalf("ACL = %+v，期望只保留原有的 ACL %+v", got, beforeACLs)
+	}
+	if created := callsOf(backend.Calls(), acltest.MethodCreate); len(created) <= len(beforeACLs) {
+		t.Fatalf("策略变化后没有创建新的 ACL，未覆盖回滚路径: %+v", created)
+	}
+}
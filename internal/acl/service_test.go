@@ -0,0 +1,101 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/networkservicemesh/govpp/binapi/acl_types"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+	"github.com/ifzzh/cmd-nse-template/internal/acl/acltest"
+)
+
+const serviceRuleSets = `
+ruleSets:
+  - name: intranet
+    rules:
+      allow 10.0.0.0/8:
+        srcprefix: 10.0.0.0/8
+        ispermit: 1
+  - name: dmz
+    rules:
+      allow 192.168.0.0/16:
+        srcprefix: 192.168.0.0/16
+        ispermit: 1
+`
+
+func TestServiceRuleSets(t *testing.T) {
+	backend := acltest.NewBackend()
+	policy := acl.NewPolicy(parseRuleSets(t, serviceRuleSets))
+	server := newTestServer(backend, policy, acl.WithServiceRuleSets(map[string][]string{"firewall": {"intranet"}}))
+
+	if _, err := server.Request(context.Background(), testRequest("conn-1")); err != nil {
+		t.Fatalf("Request 失败: %v", err)
+	}
+
+	input, _ := boundRules(t, backend)
+	if len(input) != 1 {
+		t.Fatalf("入站 ACL 数量 = %d，期望 1", len(input))
+	}
+	for src, want := range map[string]acl_types.ACLAction{
+		"10.0.0.2":    acl_types.ACL_ACTION_API_PERMIT,
+		"192.168.1.1": acl_types.ACL_ACTION_API_DENY,
+	} {
+		if got := firstMatch(t, input[0], src); got != want {
+			t.Errorf("源地址 %s 的动作 = %v，期望 %v", src, got, want)
+		}
+	}
+}
+
+func TestServiceWithMissingRuleSetDeniesAll(t *testing.T) {
+	backend := acltest.NewBackend()
+	policy := acl.NewPolicy(parseRuleSets(t, serviceRuleSets))
+	server := newTestServer(backend, policy, acl.WithServiceRuleSets(map[string][]string{"firewall": {"intranet"}}))
+
+	if _, err := server.Request(context.Background(), testRequest("conn-1")); err != nil {
+		t.Fatalf("Request 失败: %v", err)
+	}
+
+	// 远程策略删除了网络服务选择的规则集
+	sets := policy.RuleSets()
+	policy.SetRuleSets(context.Background(), sets[1:])
+
+	input, output := boundRules(t, backend)
+	if len(input) != 1 || len(output) != 1 {
+		t.Fatalf("入站/出站 ACL 数量 = %d/%d，期望 1/1（不能释放 ACL）", len(input), len(output))
+	}
+	for _, src := range []string{"10.0.0.2", "192.168.1.1", "2001:db8::1"} {
+		if got := firstMatch(t, input[0], src); got != acl_types.ACL_ACTION_API_DENY {
+			t.Errorf("源地址 %s 的动作 = %v，期望拒绝", src, got)
+		}
+	}
+
+	// 恢复规则集后按策略重新下发
+	policy.SetRuleSets(context.Background(), sets)
+	input, _ = boundRules(t, backend)
+	if len(input) != 1 {
+		t.Fatalf("入站 ACL 数量 = %d，期望 1", len(input))
+	}
+	if got := firstMatch(t, input[0], "10.0.0.2"); got != acl_types.ACL_ACTION_API_PERMIT {
+		t.Errorf("恢复规则集后源地址 10.0.0.2 的动作 = %v，期望允许", got)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+
+	"github.com/networkservicemesh/govpp/binapi/interface_types"
+	"github.com/networkservicemesh/govpp/binapi/vlib"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// showSessionsCmd 查看 ACL 插件会话表的 VPP CLI 命令
+const showSessionsCmd = "show acl-plugin sessions"
+
+// sessionLine 匹配 show acl-plugin sessions 输出中每个 worker 线程的接口会话统计行，
+// 如 "    sw_if_index 1: add 10 - del 4 = 6"
+var sessionLine = regexp.MustCompile(`sw_if_index (\d+): add \d+ - del \d+ = (\d+)`)
+
+// trackSessions 登记需要采集会话数的连接及其接口
+func (c *RuleCounters) trackSessions(connID string, swIfIndex interface_types.InterfaceIndex) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interfaces[connID] = swIfIndex
+}
+
+// untrackSessions 注销连接的会话数采集
+func (c *RuleCounters) untrackSessions(connID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.interfaces, connID)
+	delete(c.sessions, connID)
+}
+
+// Sessions 返回每个连接当前的 ACL 插件会话数
+func (c *RuleCounters) Sessions() map[string]uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	rv := make(map[string]uint64, len(c.sessions))
+	for connID, n := range c.sessions {
+		rv[connID] = n
+	}
+	return rv
+}
+
+// collectSessions 读取一次 ACL 插件会话表并更新已登记连接的会话数
+//
+// 技术细节:
+//
+//	ACL 插件没有按接口查询会话数的二进制 API，这里通过 cli_inband 执行
+//	show acl-plugin sessions，累加各 worker 线程中同一 sw_if_index 的活动会话数。
+func (c *RuleCounters) collectSessions(ctx context.Context) error {
+	c.mu.RLock()
+	tracked := len(c.interfaces)
+	c.mu.RUnlock()
+	if tracked == 0 {
+		return nil
+	}
+
+	rsp, err := vlib.NewServiceClient(c.vppConn).CliInband(ctx, &vlib.CliInband{Cmd: showSessionsCmd})
+	if err != nil {
+		return errors.Wrap(err, "VPP API CliInband 调用失败")
+	}
+	perInterface := parseSessions(rsp.Reply)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for connID, swIfIndex := range c.interfaces {
+		c.sessions[connID] = perInterface[swIfIndex]
+	}
+	return nil
+}
+
+// parseSessions 解析 show acl-plugin sessions 的输出，返回每个接口的活动会话数
+func parseSessions(reply string) map[interface_types.InterfaceIndex]uint64 {
+	rv := make(map[interface_types.InterfaceIndex]uint64)
+	for _, m := range sessionLine.FindAllStringSubmatch(reply, -1) {
+		swIfIndex, err := strconv.ParseUint(m[1], 10, 32)
+		if err != nil {
+			continue
+		}
+		n, err := strconv.ParseUint(m[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		rv[interface_types.InterfaceIndex(swIfIndex)] += n
+	}
+	return rv
+}
+
+// registerSessionMetrics 将每个连接的会话数注册为 OpenTelemetry 异步仪表
+func (c *RuleCounters) registerSessionMetrics(ctx context.Context) {
+	logger := log.FromContext(ctx).WithField("acl_counters", "metrics")
+	meter := otel.Meter("")
+
+	sessions, err := meter.Int64ObservableGauge("acl_sessions",
+		metric.WithDescription("Number of active ACL plugin sessions created by stateful firewall rules"))
+	if err != nil {
+		logger.Errorf("创建指标失败: %v", err)
+		return
+	}
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for connID, n := range c.Sessions() {
+			o.ObserveInt64(sessions, int64(n), metric.WithAttributes(attribute.String("connection", connID)))
+		}
+		return nil
+	}, sessions)
+	if err != nil {
+		logger.Errorf("注册指标回调失败: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ruleState 状态文件的格式
+//
+// ConfigDigest 为保存状态时配置文件内容的摘要（见 ConfigDigest），RuleSets 与配置文件的 ruleSets 格式相同，
+// TemporaryRules 为尚未删除的临时规则及其到期时间。
+type ruleState struct {
+	ConfigDigest   string                `yaml:"configDigest"`
+	RuleSets       []ruleSetConfig       `yaml:"ruleSets"`
+	TemporaryRules []temporaryRuleConfig `yaml:"temporaryRules,omitempty"`
+}
+
+// ConfigDigest 返回配置文件内容的摘要
+func ConfigDigest(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// SaveState 将运行时修改后的规则集和临时规则写入状态文件
+//
+// 先写临时文件再重命名，避免进程中途退出时留下不完整的状态文件。
+//
+// 参数:
+//   - path: 状态文件路径
+//   - digest: 当前配置文件内容的摘要
+//   - rules: 规则集和临时规则
+//
+// 返回:
+//   - error: 错误信息
+func SaveState(path, digest string, rules *ConfiguredRules) error {
+	raw, err := yaml.Marshal(ruleState{
+		ConfigDigest:   digest,
+		RuleSets:       ruleSetConfigs(rules.RuleSets),
+		TemporaryRules: temporaryConfigs(rules.Temporary),
+	})
+	if err != nil {
+		return errors.Wrap(err, "序列化规则状态失败")
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return errors.Wrap(err, "创建状态文件目录失败")
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return errors.Wrap(err, "创建状态文件失败")
+	}
+	_, err = tmp.Write(raw)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return errors.Wrap(err, "写入状态文件失败")
+	}
+	return nil
+}
+
+// LoadState 读取状态文件中保存的规则集和临时规则
+//
+// 功能说明:
+//   - 状态文件不存在时返回 nil（没有运行时修改）
+//   - 状态文件基于其他内容的配置文件保存时返回错误，配置文件优先
+//   - 规则集和临时规则按配置文件的规则重新校验，已到期的临时规则直接丢弃
+//
+// 参数:
+//   - path: 状态文件路径
+//   - digest: 当前配置文件内容的摘要
+//
+// 返回:
+//   - *ConfiguredRules: 保存的规则集和临时规则
+//   - error: 错误信息
+func LoadState(path, digest string) (*ConfiguredRules, error) {
+	raw, err := os.ReadFile(filepath.Clean(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "读取状态文件失败")
+	}
+	var state ruleState
+	if err = yaml.Unmarshal(raw, &state); err != nil {
+		return nil, errors.Wrap(err, "解析状态文件失败")
+	}
+	if state.ConfigDigest != digest {
+		return nil, errors.New("配置文件在保存状态后已变化")
+	}
+	rules := new(ConfiguredRules)
+	if rules.RuleSets, err = buildRuleSets(state.RuleSets); err != nil {
+		return nil, err
+	}
+	if rules.Temporary, err = buildTemporary(state.TemporaryRules, time.Now()); err != nil {
+		return nil, err
+	}
+	if err = validateRules(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
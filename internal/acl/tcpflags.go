@@ -0,0 +1,119 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"strings"
+
+	"github.com/networkservicemesh/govpp/binapi/ip_types"
+	"github.com/pkg/errors"
+)
+
+// TCP 标志位
+const (
+	tcpFlagFIN uint8 = 0x01
+	tcpFlagSYN uint8 = 0x02
+	tcpFlagRST uint8 = 0x04
+	tcpFlagPSH uint8 = 0x08
+	tcpFlagACK uint8 = 0x10
+	tcpFlagURG uint8 = 0x20
+	tcpFlagECE uint8 = 0x40
+	tcpFlagCWR uint8 = 0x80
+)
+
+// tcpFlagNames 配置中可用的 TCP 标志名称
+var tcpFlagNames = map[string]uint8{
+	"fin": tcpFlagFIN,
+	"syn": tcpFlagSYN,
+	"rst": tcpFlagRST,
+	"psh": tcpFlagPSH,
+	"ack": tcpFlagACK,
+	"urg": tcpFlagURG,
+	"ece": tcpFlagECE,
+	"cwr": tcpFlagCWR,
+}
+
+// tcpFlagEstablished 已建立连接的报文（ACK 置位），即除首个 SYN 以外的全部报文
+const tcpFlagEstablished = "established"
+
+// parseTCPFlags 将命名的 TCP 标志条件转换为 VPP ACL 规则的掩码和值
+//
+// 功能说明:
+//   - "syn" 等标志名称: 要求该标志置位
+//   - "!ack" 等带 ! 前缀的名称: 要求该标志清零
+//   - "established": 要求 ACK 置位
+//
+// 参数:
+//   - flags: 标志条件列表，如 ["syn", "!ack"]
+//
+// 返回:
+//   - mask: TCPFlagsMask，参与匹配的标志位
+//   - value: TCPFlagsValue，参与匹配的标志位的期望值
+//   - err: 未知名称或同一标志的条件互相矛盾
+func parseTCPFlags(flags []string) (mask, value uint8, err error) {
+	for _, flag := range flags {
+		name := strings.ToLower(strings.TrimSpace(flag))
+		if name == tcpFlagEstablished {
+			name = "ack"
+		}
+		set := !strings.HasPrefix(name, "!")
+		bit, ok := tcpFlagNames[strings.TrimPrefix(name, "!")]
+		if !ok {
+			return 0, 0, errors.Errorf("未知的 TCP 标志 %q", flag)
+		}
+		if mask&bit != 0 && (value&bit != 0) != set {
+			return 0, 0, errors.Errorf("TCP 标志 %q 与其他条件矛盾", flag)
+		}
+		mask |= bit
+		if set {
+			value |= bit
+		}
+	}
+	return mask, value, nil
+}
+
+// applyTCPFlags 将规则中命名的 TCP 标志条件写入 TCPFlagsMask 和 TCPFlagsValue
+func (r *Rule) applyTCPFlags() error {
+	if len(r.TCPFlags) == 0 {
+		return nil
+	}
+	if r.Proto != ip_types.IP_API_PROTO_TCP {
+		return errors.New("tcpflags 只能用于 TCP 规则（proto: 6）")
+	}
+	mask, value, err := parseTCPFlags(r.TCPFlags)
+	if err != nil {
+		return err
+	}
+	r.TCPFlagsMask |= mask
+	r.TCPFlagsValue = r.TCPFlagsValue&^mask | value
+	return nil
+}
+
+// mirrorTCPFlags 返回出站镜像规则的 TCP 标志值
+//
+// 入站规则匹配发起连接的 SYN（syn,!ack）时，反方向对应的报文是 SYN-ACK，
+// 因此镜像规则改为匹配 syn,ack；其余标志条件在两个方向上相同。
+func mirrorTCPFlags(mask, value uint8) uint8 {
+	const synAck = tcpFlagSYN | tcpFlagACK
+	if mask&synAck == synAck && value&synAck == tcpFlagSYN {
+		return value | tcpFlagACK
+	}
+	return value
+}
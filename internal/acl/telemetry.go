@@ -0,0 +1,160 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.fd.io/govpp/api"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
+)
+
+// VPP ACL API 调用结果，用作指标的 result 标签
+const (
+	resultOK       = "ok"
+	resultVPPError = "vpp_error"
+	resultTimeout  = "timeout"
+	resultCanceled = "canceled"
+	resultError    = "error"
+)
+
+// apiMetrics VPP ACL API 调用指标
+type apiMetrics struct {
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+var (
+	apiMetricsOnce sync.Once
+	apiMetricsInst *apiMetrics
+)
+
+// getAPIMetrics 返回 VPP ACL API 调用指标，OpenTelemetry 未启用时返回 nil
+//
+// 指标在首次调用时创建，此时 main 已经设置好全局 MeterProvider。
+func getAPIMetrics(ctx context.Context) *apiMetrics {
+	apiMetricsOnce.Do(func() {
+		if !opentelemetry.IsEnabled() {
+			return
+		}
+		logger := log.FromContext(ctx).WithField("acl", "metrics")
+		meter := otel.Meter("")
+
+		duration, err := meter.Float64Histogram("acl_vpp_api_duration_seconds",
+			metric.WithDescription("Duration of VPP ACL API calls made by the firewall"),
+			metric.WithUnit("s"),
+			metric.WithExplicitBucketBoundaries(0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5))
+		if err != nil {
+			logger.Errorf("创建指标失败: %v", err)
+			return
+		}
+		errCounter, err := meter.Int64Counter("acl_vpp_api_errors",
+			metric.WithDescription("Number of failed VPP ACL API calls made by the firewall"))
+		if err != nil {
+			logger.Errorf("创建指标失败: %v", err)
+			return
+		}
+		apiMetricsInst = &apiMetrics{duration: duration, errors: errCounter}
+	})
+	return apiMetricsInst
+}
+
+// callVPP 调用一次 VPP ACL API，并记录耗时、错误和追踪 span
+//
+// 功能说明:
+//   - 为调用创建 OpenTelemetry span，附带连接 ID 和 API 名称
+//   - 按 API 名称和调用结果记录耗时直方图，失败时累加错误计数
+//   - 以 debug 级别记录调用耗时
+//   - 按重试策略（见 withCallTimeout）限制单次调用的超时
+//
+// 参数:
+//   - ctx: 上下文
+//   - connID: 连接 ID
+//   - apiName: VPP API 名称（如 ACLAddReplace）
+//   - fn: 实际的 VPP API 调用
+//
+// 返回:
+//   - error: fn 返回的错误
+func callVPP(ctx context.Context, connID, apiName string, fn func(ctx context.Context) error) error {
+	ctx, span := otel.Tracer("").Start(ctx, "vpp."+apiName, trace.WithAttributes(
+		attribute.String("connection", connID),
+		attribute.String("vppapi", apiName),
+	))
+	defer span.End()
+
+	callCtx, cancel := callContext(ctx)
+	defer cancel()
+
+	now := time.Now()
+	err := fn(callCtx)
+	duration := time.Since(now)
+	result := callResult(err)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(attribute.String("result", result))
+
+	if m := getAPIMetrics(ctx); m != nil {
+		attrs := metric.WithAttributes(
+			attribute.String("api", apiName),
+			attribute.String("result", result),
+		)
+		m.duration.Record(ctx, duration.Seconds(), attrs)
+		if err != nil {
+			m.errors.Add(ctx, 1, attrs)
+		}
+	}
+
+	log.FromContext(ctx).
+		WithField("connection", connID).
+		WithField("duration", duration).
+		WithField("result", result).
+		WithField("vppapi", apiName).Debug("VPP API 调用完成")
+	return err
+}
+
+// callResult 将 VPP API 调用错误归类为 result 标签
+func callResult(err error) string {
+	var vppErr api.VPPApiError
+	switch {
+	case err == nil:
+		return resultOK
+	case errors.As(err, &vppErr):
+		return resultVPPError
+	case errors.Is(err, context.DeadlineExceeded):
+		return resultTimeout
+	case errors.Is(err, context.Canceled):
+		return resultCanceled
+	default:
+		return resultError
+	}
+}
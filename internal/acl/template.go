@@ -0,0 +1,202 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/govpp/binapi/ip_types"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// 规则模板中可用的占位符，在 aclServer.Request 中按连接替换
+const (
+	// placeholderClientIP NSC 的地址（IpContext.SrcIpAddrs）
+	placeholderClientIP = "${client.ip}"
+	// placeholderEndpointIP 端点的地址（IpContext.DstIpAddrs）
+	placeholderEndpointIP = "${endpoint.ip}"
+)
+
+// placeholderPattern 匹配模板中的占位符
+var placeholderPattern = regexp.MustCompile(`\$\{[^}]*\}`)
+
+// 可以使用模板的规则字段
+const (
+	fieldSrcPrefix = "srcprefix"
+	fieldDstPrefix = "dstprefix"
+)
+
+// extractTemplates 从规则字段中取出带占位符的 srcprefix/dstprefix
+//
+// 参数:
+//   - fields: 配置文件中规则的全部字段
+//
+// 返回:
+//   - yaml.MapSlice: 去掉模板字段后的其余字段
+//   - string: srcprefix 模板（没有时为空）
+//   - string: dstprefix 模板（没有时为空）
+//   - error: 模板中有未知的占位符
+func extractTemplates(fields yaml.MapSlice) (rest yaml.MapSlice, srcTemplate, dstTemplate string, err error) {
+	for _, item := range fields {
+		value, ok := item.Value.(string)
+		if !ok || !strings.Contains(value, "${") {
+			rest = append(rest, item)
+			continue
+		}
+		for _, placeholder := range placeholderPattern.FindAllString(value, -1) {
+			if placeholder != placeholderClientIP && placeholder != placeholderEndpointIP {
+				return nil, "", "", errors.Errorf("未知的占位符 %s，可用: %s, %s", placeholder, placeholderClientIP, placeholderEndpointIP)
+			}
+		}
+		switch item.Key {
+		case fieldSrcPrefix:
+			srcTemplate = value
+		case fieldDstPrefix:
+			dstTemplate = value
+		default:
+			return nil, "", "", errors.Errorf("字段 %v 不支持占位符", item.Key)
+		}
+	}
+	return rest, srcTemplate, dstTemplate, nil
+}
+
+// resolveRules 用连接的地址替换规则模板中的占位符
+//
+// 功能说明:
+//   - 没有模板的规则保持不变
+//   - 占位符对应多个地址时，规则按地址展开为多条（源 × 目标），名称不变
+//   - 占位符没有可用地址时跳过该规则
+//   - 另一侧前缀未配置且地址族不同时，改为对应地址族的任意地址
+//
+// 参数:
+//   - rules: 配置的规则列表
+//   - ipContext: 连接的 IP 上下文
+//
+// 返回:
+//   - []Rule: 替换后的规则列表
+//   - error: 替换后的前缀格式错误
+func resolveRules(rules []Rule, ipContext *networkservice.IPContext) ([]Rule, error) {
+	rv := make([]Rule, 0, len(rules))
+	for i := range rules {
+		if rules[i].SrcPrefixTemplate == "" && rules[i].DstPrefixTemplate == "" {
+			rv = append(rv, rules[i])
+			continue
+		}
+		srcs, err := expand(rules[i].SrcPrefixTemplate, rules[i].SrcPrefix, ipContext)
+		if err != nil {
+			return nil, errors.Wrapf(err, "规则 %q", rules[i].Name)
+		}
+		dsts, err := expand(rules[i].DstPrefixTemplate, rules[i].DstPrefix, ipContext)
+		if err != nil {
+			return nil, errors.Wrapf(err, "规则 %q", rules[i].Name)
+		}
+		for _, src := range srcs {
+			for _, dst := range dsts {
+				srcPrefix, dstPrefix, ok := sameFamily(src, dst)
+				if !ok {
+					continue
+				}
+				r := rules[i]
+				r.SrcPrefixTemplate, r.DstPrefixTemplate = "", ""
+				r.SrcPrefix, r.DstPrefix = srcPrefix, dstPrefix
+				rv = append(rv, r)
+			}
+		}
+	}
+	return rv, nil
+}
+
+// expand 返回模板替换后的全部前缀，没有模板时返回 prefix 本身
+func expand(template string, prefix ip_types.Prefix, ipContext *networkservice.IPContext) ([]ip_types.Prefix, error) {
+	if template == "" {
+		return []ip_types.Prefix{prefix}, nil
+	}
+	var placeholder string
+	var addrs []string
+	switch {
+	case strings.Contains(template, placeholderClientIP):
+		placeholder, addrs = placeholderClientIP, ipContext.GetSrcIpAddrs()
+	case strings.Contains(template, placeholderEndpointIP):
+		placeholder, addrs = placeholderEndpointIP, ipContext.GetDstIpAddrs()
+	}
+
+	var rv []ip_types.Prefix
+	for _, addr := range addrs {
+		ip, err := addrOf(addr)
+		if err != nil {
+			return nil, err
+		}
+		value := strings.ReplaceAll(template, placeholder, ip.String())
+		if !strings.Contains(value, "/") {
+			value += "/" + strconv.Itoa(ip.BitLen())
+		}
+		p, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "模板 %q 替换后的前缀无效", template)
+		}
+		vppPrefix, err := ip_types.ParsePrefix(p.Masked().String())
+		if err != nil {
+			return nil, errors.Wrapf(err, "模板 %q 替换后的前缀无效", template)
+		}
+		rv = append(rv, vppPrefix)
+	}
+	return rv, nil
+}
+
+// addrOf 返回 IpContext 地址（带或不带前缀长度）中的 IP 地址
+func addrOf(addr string) (netip.Addr, error) {
+	if p, err := netip.ParsePrefix(addr); err == nil {
+		return p.Addr(), nil
+	}
+	ip, err := netip.ParseAddr(addr)
+	return ip, errors.Wrapf(err, "无效的地址 %q", addr)
+}
+
+// sameFamily 使源/目标前缀属于同一地址族
+//
+// 未配置的前缀（零值，即 0.0.0.0/0）改为另一侧地址族的任意地址；
+// 两侧都已配置且地址族不同时返回 false。
+func sameFamily(src, dst ip_types.Prefix) (ip_types.Prefix, ip_types.Prefix, bool) {
+	if src.Address.Af == dst.Address.Af {
+		return src, dst, true
+	}
+	if isUnset(dst) {
+		return src, anyPrefix(src.Address.Af), true
+	}
+	if isUnset(src) {
+		return anyPrefix(dst.Address.Af), dst, true
+	}
+	return src, dst, false
+}
+
+// isUnset 判断前缀是否为未配置的零值
+func isUnset(p ip_types.Prefix) bool {
+	return p == ip_types.Prefix{}
+}
+
+// anyPrefix 返回地址族的任意地址前缀（0.0.0.0/0 或 ::/0）
+func anyPrefix(af ip_types.AddressFamily) ip_types.Prefix {
+	return ip_types.Prefix{Address: ip_types.Address{Af: af}}
+}
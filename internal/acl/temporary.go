@@ -0,0 +1,197 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package acl
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/extend"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// TemporaryRule 到期后自动删除的临时规则
+//
+// 临时规则排在配置的规则集之前匹配（见 Policy.Rules）；Rule.RuleSet 为规则所属的规则集，
+// 规则集的 Stateful 和 FailureMode 设置同样作用于临时规则。
+type TemporaryRule struct {
+	Rule
+	ExpiresAt time.Time
+}
+
+// temporaryRuleConfig 状态文件中一条临时规则的格式
+type temporaryRuleConfig struct {
+	Name      string        `yaml:"name"`
+	RuleSet   string        `yaml:"ruleSet"`
+	ExpiresAt string        `yaml:"expiresAt"`
+	Rule      yaml.MapSlice `yaml:"rule"`
+}
+
+// flattenTemporary 返回尚未到期的临时规则，并按所属规则集的设置展开
+//
+// 所属规则集已不存在时（如被策略服务器的策略替换），规则按无状态、FailureClosed 处理。
+func flattenTemporary(temporary []TemporaryRule, sets []RuleSet, now time.Time) []Rule {
+	var rv []Rule
+	for i := range temporary {
+		if !temporary[i].ExpiresAt.After(now) {
+			continue
+		}
+		r := temporary[i].Rule
+		r.FailureMode = FailureClosed
+		if j := findRuleSet(sets, r.RuleSet); j >= 0 {
+			r.Stateful = r.Stateful || sets[j].Stateful
+			r.FailureMode = sets[j].FailureMode
+		}
+		rv = append(rv, r)
+	}
+	return rv
+}
+
+// carryTemporary 返回 temporary 中尚未到期、且在规则集 sets 下仍然有效的临时规则（见 validateTemporary），
+// 其余的临时规则记录警告后丢弃；用于替换规则集时保留临时规则
+func carryTemporary(ctx context.Context, temporary []TemporaryRule, sets []RuleSet, now time.Time) []TemporaryRule {
+	logger := log.FromContext(ctx).WithField("acl", "temporary")
+	var rv []TemporaryRule
+	for i := range temporary {
+		if !temporary[i].ExpiresAt.After(now) {
+			continue
+		}
+		if err := validateTemporary(append(append([]TemporaryRule{}, rv...), temporary[i]), sets); err != nil {
+			logger.Warnf("替换规则集后丢弃临时规则 %q: %v", temporary[i].Name, err)
+			continue
+		}
+		rv = append(rv, temporary[i])
+	}
+	return rv
+}
+
+// validateTemporary 校验临时规则：所属规则集须存在，规则名称与规则集中的规则和其他临时规则都不重复
+func validateTemporary(temporary []TemporaryRule, sets []RuleSet) error {
+	seen := make(map[string]bool)
+	for _, set := range sets {
+		for i := range set.Rules {
+			seen[set.Rules[i].Name] = true
+		}
+	}
+	for i := range temporary {
+		r := &temporary[i]
+		if r.Name == "" {
+			return errors.New("临时规则缺少名称")
+		}
+		if findRuleSet(sets, r.RuleSet) < 0 {
+			return errors.Errorf("临时规则 %q 所属的规则集 %q 不存在", r.Name, r.RuleSet)
+		}
+		if seen[r.Name] {
+			return errors.Errorf("规则名称 %q 重复", r.Name)
+		}
+		seen[r.Name] = true
+	}
+	return nil
+}
+
+// temporaryConfigs 将临时规则转换为状态文件格式
+func temporaryConfigs(temporary []TemporaryRule) []temporaryRuleConfig {
+	cfgs := make([]temporaryRuleConfig, 0, len(temporary))
+	for i := range temporary {
+		r := &temporary[i]
+		cfgs = append(cfgs, temporaryRuleConfig{
+			Name:      r.Name,
+			RuleSet:   r.RuleSet,
+			ExpiresAt: r.ExpiresAt.UTC().Format(time.RFC3339),
+			Rule:      r.source,
+		})
+	}
+	return cfgs
+}
+
+// buildTemporary 解析状态文件中的临时规则，已到期的规则直接丢弃
+func buildTemporary(cfgs []temporaryRuleConfig, now time.Time) ([]TemporaryRule, error) {
+	var rv []TemporaryRule
+	for _, cfg := range cfgs {
+		expiresAt, err := time.Parse(time.RFC3339, cfg.ExpiresAt)
+		if err != nil {
+			return nil, errors.Wrapf(err, "临时规则 %q 的到期时间无效", cfg.Name)
+		}
+		if !expiresAt.After(now) {
+			continue
+		}
+		raw, err := yaml.Marshal(cfg.Rule)
+		if err != nil {
+			return nil, errors.Wrapf(err, "临时规则 %q", cfg.Name)
+		}
+		rule, err := ParseRule(cfg.Name, raw)
+		if err != nil {
+			return nil, err
+		}
+		rule.RuleSet = cfg.RuleSet
+		rv = append(rv, TemporaryRule{Rule: rule, ExpiresAt: expiresAt})
+	}
+	return rv, nil
+}
+
+// scheduleExpiry 按最早到期的临时规则重新设置到期定时器，调用方需持有 p.mu
+//
+// ctx 只用于携带日志等上下文值，定时器触发时使用不会被取消的上下文（见 extend.WithValuesFromContext）。
+func (p *Policy) scheduleExpiry(ctx context.Context) {
+	if p.expiry != nil {
+		p.expiry.Stop()
+		p.expiry = nil
+	}
+	var next time.Time
+	for i := range p.temporary {
+		if next.IsZero() || p.temporary[i].ExpiresAt.Before(next) {
+			next = p.temporary[i].ExpiresAt
+		}
+	}
+	if next.IsZero() {
+		return
+	}
+	expireCtx := extend.WithValuesFromContext(context.Background(), ctx)
+	p.expiry = time.AfterFunc(time.Until(next), func() {
+		p.expire(expireCtx)
+	})
+}
+
+// expire 删除已到期的临时规则并通知订阅者，ACL 服务器据此从已有连接的 ACL 中移除这些规则
+func (p *Policy) expire(ctx context.Context) {
+	logger := log.FromContext(ctx).WithField("acl", "expire")
+	now := time.Now()
+
+	p.mu.Lock()
+	kept := p.temporary[:0:0]
+	for i := range p.temporary {
+		if p.temporary[i].ExpiresAt.After(now) {
+			kept = append(kept, p.temporary[i])
+			continue
+		}
+		logger.Infof("临时规则 %q（规则集 %q）已到期，删除", p.temporary[i].Name, p.temporary[i].RuleSet)
+	}
+	expired := len(kept) != len(p.temporary)
+	p.temporary = kept
+	p.scheduleExpiry(ctx)
+	p.mu.Unlock()
+
+	if expired {
+		p.notify(ctx)
+	}
+}
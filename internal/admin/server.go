@@ -0,0 +1,163 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package admin 提供防火墙 NSE 的本地管理 HTTP 端点
+// 用于查询 ACL 规则命中计数等运行时数据
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/pkg/errors"
+
+	"github.com/ifzzh/cmd-nse-template/internal"
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+	"github.com/ifzzh/cmd-nse-template/internal/audit"
+	"github.com/ifzzh/cmd-nse-template/internal/blocklist"
+	"github.com/ifzzh/cmd-nse-template/internal/remotepolicy"
+)
+
+// Option 管理端点的可选配置项
+type Option func(mux *http.ServeMux)
+
+// WithRuleCounters 注册规则命中计数查询接口
+//
+// GET /acl/counters[?connection=<连接 ID>] 返回 JSON 格式的逐条规则命中计数
+func WithRuleCounters(counters *acl.RuleCounters) Option {
+	return func(mux *http.ServeMux) {
+		mux.HandleFunc("/acl/counters", func(w http.ResponseWriter, r *http.Request) {
+			hits := counters.Snapshot()
+			if connID := r.URL.Query().Get("connection"); connID != "" {
+				filtered := hits[:0]
+				for _, hit := range hits {
+					if hit.ConnectionID == connID {
+						filtered = append(filtered, hit)
+					}
+				}
+				hits = filtered
+			}
+			writeJSON(w, hits)
+		})
+	}
+}
+
+// WithDeadRules 注册未命中规则报告接口
+//
+// GET /acl/dead-rules[?window=<时间窗口>] 返回时间窗口内未命中任何流量的规则，
+// 未指定 window 时使用 defaultWindow
+func WithDeadRules(counters *acl.RuleCounters, defaultWindow time.Duration) Option {
+	return func(mux *http.ServeMux) {
+		mux.HandleFunc("/acl/dead-rules", func(w http.ResponseWriter, r *http.Request) {
+			window := defaultWindow
+			if v := r.URL.Query().Get("window"); v != "" {
+				var err error
+				if window, err = time.ParseDuration(v); err != nil || window <= 0 {
+					http.Error(w, "无效的时间窗口: "+v, http.StatusBadRequest)
+					return
+				}
+			}
+			writeJSON(w, counters.DeadRules(window))
+		})
+	}
+}
+
+// WithBlocklists 注册 IP 黑名单统计接口
+//
+// GET /acl/blocklists 返回 JSON 格式的各黑名单文件条目数和聚合后的前缀数
+func WithBlocklists(blocklists *blocklist.Blocklists) Option {
+	return func(mux *http.ServeMux) {
+		mux.HandleFunc("/acl/blocklists", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, blocklists.Stats())
+		})
+	}
+}
+
+// WithPolicyPoller 注册策略服务器轮询状态接口
+//
+// GET /acl/policy 返回 JSON 格式的当前策略来源、ETag、规则数和最近一次轮询结果
+func WithPolicyPoller(poller *remotepolicy.Poller) Option {
+	return func(mux *http.ServeMux) {
+		mux.HandleFunc("/acl/policy", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, poller.Status())
+		})
+	}
+}
+
+// WithRegistration 注册端点注册状态查询接口
+//
+// GET /registration 返回 JSON 格式的端点注册状态（是否有效、到期时间、最近一次刷新和连续失败次数）
+func WithRegistration(registration *internal.Registration) Option {
+	return func(mux *http.ServeMux) {
+		mux.HandleFunc("/registration", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, registration.Status())
+		})
+	}
+}
+
+// WithAuditEvents 注册审计事件查询接口
+//
+// GET /audit 返回 JSON 格式的最近审计事件（如被拒绝的策略）
+func WithAuditEvents() Option {
+	return func(mux *http.ServeMux) {
+		mux.HandleFunc("/audit", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, audit.Events())
+		})
+	}
+}
+
+// NewHandler 创建管理端点的 HTTP 处理器
+func NewHandler(options ...Option) http.Handler {
+	mux := http.NewServeMux()
+	for _, opt := range options {
+		opt(mux)
+	}
+	return mux
+}
+
+// ListenAndServe 在 listenOn 上启动管理 HTTP 服务，ctx 结束时关闭服务
+func ListenAndServe(ctx context.Context, listenOn string, handler http.Handler) {
+	logger := log.FromContext(ctx).WithField("admin", "ListenAndServe")
+	server := &http.Server{
+		Addr:         listenOn,
+		Handler:      handler,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	logger.Infof("管理端点已启用，监听地址: %s", listenOn)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Errorf("管理端点启动失败: %s", err.Error())
+	}
+}
+
+// writeJSON 以 JSON 格式写出响应
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
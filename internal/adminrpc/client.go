@@ -0,0 +1,146 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package adminrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+)
+
+// Client 管理接口客户端
+type Client struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewClient 创建管理接口客户端
+//
+// cc 须通过 WithCodec 拨号（或在每次调用时传入 ForceCodec），以 JSON 编码消息。
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{cc: cc}
+}
+
+// WithCodec 返回使用管理接口编解码器的拨号选项
+func WithCodec() grpc.DialOption {
+	return grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{}))
+}
+
+// ListConnections 列出全部连接及其 ACL 状态
+func (c *Client) ListConnections(ctx context.Context, opts ...grpc.CallOption) ([]acl.ConnectionInfo, error) {
+	rsp := new(ListConnectionsResponse)
+	if err := c.cc.Invoke(ctx, fullMethod(listConnectionsMethod), &ListConnectionsRequest{}, rsp, opts...); err != nil {
+		return nil, err
+	}
+	return rsp.Connections, nil
+}
+
+// GetConnectionRules 返回连接已下发的完整规则
+func (c *Client) GetConnectionRules(ctx context.Context, connID string, opts ...grpc.CallOption) (*acl.ConnectionRules, error) {
+	rsp := new(acl.ConnectionRules)
+	if err := c.cc.Invoke(ctx, fullMethod(getConnectionRulesMethod), &GetConnectionRulesRequest{ConnectionID: connID}, rsp, opts...); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// QuarantineConnection 隔离连接，返回隔离后的连接状态
+func (c *Client) QuarantineConnection(ctx context.Context, connID string, opts ...grpc.CallOption) (*acl.ConnectionInfo, error) {
+	rsp := new(acl.ConnectionInfo)
+	if err := c.cc.Invoke(ctx, fullMethod(quarantineMethod), &ConnectionRequest{ConnectionID: connID}, rsp, opts...); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// ReleaseConnection 解除连接的隔离，返回解除后的连接状态
+func (c *Client) ReleaseConnection(ctx context.Context, connID string, opts ...grpc.CallOption) (*acl.ConnectionInfo, error) {
+	rsp := new(acl.ConnectionInfo)
+	if err := c.cc.Invoke(ctx, fullMethod(releaseMethod), &ConnectionRequest{ConnectionID: connID}, rsp, opts...); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// GetEmergencyMode 返回全局紧急模式状态
+func (c *Client) GetEmergencyMode(ctx context.Context, opts ...grpc.CallOption) (*acl.EmergencyStatus, error) {
+	rsp := new(acl.EmergencyStatus)
+	if err := c.cc.Invoke(ctx, fullMethod(getEmergencyModeMethod), &GetEmergencyModeRequest{}, rsp, opts...); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// SetEmergencyMode 切换全局紧急模式，返回切换后的状态
+func (c *Client) SetEmergencyMode(ctx context.Context, mode acl.EmergencyMode, opts ...grpc.CallOption) (*acl.EmergencyStatus, error) {
+	rsp := new(acl.EmergencyStatus)
+	if err := c.cc.Invoke(ctx, fullMethod(setEmergencyModeMethod), &SetEmergencyModeRequest{Mode: mode}, rsp, opts...); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// ListRuleSets 列出配置的规则集及其规则
+func (c *Client) ListRuleSets(ctx context.Context, opts ...grpc.CallOption) ([]acl.RuleSetInfo, error) {
+	rsp := new(ListRuleSetsResponse)
+	if err := c.cc.Invoke(ctx, fullMethod(listRuleSetsMethod), &ListRuleSetsRequest{}, rsp, opts...); err != nil {
+		return nil, err
+	}
+	return rsp.RuleSets, nil
+}
+
+// ListTemporaryRules 列出临时规则及其剩余有效期
+func (c *Client) ListTemporaryRules(ctx context.Context, opts ...grpc.CallOption) ([]acl.TemporaryRuleInfo, error) {
+	rsp := new(ListTemporaryRulesResponse)
+	if err := c.cc.Invoke(ctx, fullMethod(listTemporaryRulesMethod), &ListTemporaryRulesRequest{}, rsp, opts...); err != nil {
+		return nil, err
+	}
+	return rsp.Rules, nil
+}
+
+// AddRule 向规则集添加规则（设置了 TTL 时添加临时规则），返回修改后的规则集
+func (c *Client) AddRule(ctx context.Context, req *AddRuleRequest, opts ...grpc.CallOption) (*acl.RuleSetInfo, error) {
+	return c.updateRule(ctx, addRuleMethod, req, opts...)
+}
+
+// RemoveRule 从规则集删除规则，返回修改后的规则集
+func (c *Client) RemoveRule(ctx context.Context, req *RemoveRuleRequest, opts ...grpc.CallOption) (*acl.RuleSetInfo, error) {
+	return c.updateRule(ctx, removeRuleMethod, req, opts...)
+}
+
+// MoveRule 移动规则在规则集中的位置，返回修改后的规则集
+func (c *Client) MoveRule(ctx context.Context, req *MoveRuleRequest, opts ...grpc.CallOption) (*acl.RuleSetInfo, error) {
+	return c.updateRule(ctx, moveRuleMethod, req, opts...)
+}
+
+// ReplaceRule 替换规则集中规则的内容，返回修改后的规则集
+func (c *Client) ReplaceRule(ctx context.Context, req *ReplaceRuleRequest, opts ...grpc.CallOption) (*acl.RuleSetInfo, error) {
+	return c.updateRule(ctx, replaceRuleMethod, req, opts...)
+}
+
+// updateRule 调用修改规则的方法
+func (c *Client) updateRule(ctx context.Context, method string, req interface{}, opts ...grpc.CallOption) (*acl.RuleSetInfo, error) {
+	rsp := new(acl.RuleSetInfo)
+	if err := c.cc.Invoke(ctx, fullMethod(method), req, rsp, opts...); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
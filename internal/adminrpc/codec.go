@@ -0,0 +1,45 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package adminrpc
+
+import (
+	"encoding/json"
+)
+
+// codecName 编解码器名称，即 gRPC 的 content-subtype
+const codecName = "json"
+
+// jsonCodec 以 JSON 编码 gRPC 消息
+//
+// 管理接口的消息是普通的 Go 结构体（与管理 HTTP 端点返回的 JSON 相同），
+// 不需要生成 protobuf 代码；服务端和客户端都通过 ForceCodec 固定使用此编解码器。
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package adminrpc
+
+import (
+	"context"
+	"crypto/tls"
+
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+)
+
+// NewServer 创建管理 gRPC 服务器
+//
+// 功能说明:
+//   - 使用 source 提供的 X.509 SVID 和信任包进行 mTLS 认证
+//   - 只允许 SPIFFE ID 在 allowed 中的调用方，其他调用返回 PermissionDenied
+//   - 消息以 JSON 编码（见 jsonCodec）
+//
+// 参数:
+//   - source: SPIFFE X.509 SVID 来源
+//   - allowed: 允许调用的 SPIFFE ID 列表
+//   - controller: ACL 控制器
+//
+// 返回:
+//   - *grpc.Server: 已注册管理接口的 gRPC 服务器
+func NewServer(source *workloadapi.X509Source, allowed []spiffeid.ID, controller *acl.Controller) *grpc.Server {
+	tlsConfig := tlsconfig.MTLSServerConfig(source, source, tlsconfig.AuthorizeAny())
+	tlsConfig.MinVersion = tls.VersionTLS12
+	server := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpc.ForceServerCodec(jsonCodec{}),
+		grpc.UnaryInterceptor(authorize(allowed)),
+	)
+	server.RegisterService(&serviceDesc, &adminServer{controller: controller})
+	return server
+}
+
+// authorize 返回按调用方 SPIFFE ID 鉴权的拦截器
+func authorize(allowed []spiffeid.ID) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		id, err := peerID(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		for _, allowedID := range allowed {
+			if id == allowedID {
+				return handler(ctx, req)
+			}
+		}
+		log.FromContext(ctx).
+			WithField("adminrpc", "authorize").
+			WithField("spiffeID", id.String()).
+			Warnf("拒绝未授权的管理接口调用: %s", info.FullMethod)
+		return nil, status.Errorf(codes.PermissionDenied, "%s 无权调用管理接口", id)
+	}
+}
+
+// ParseIDs 解析 SPIFFE ID 列表
+func ParseIDs(ids []string) ([]spiffeid.ID, error) {
+	rv := make([]spiffeid.ID, 0, len(ids))
+	for _, s := range ids {
+		id, err := spiffeid.FromString(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "无效的 SPIFFE ID %q", s)
+		}
+		rv = append(rv, id)
+	}
+	return rv, nil
+}
+
+// peerID 从 mTLS 连接的对端证书中取出 SPIFFE ID
+func peerID(ctx context.Context) (spiffeid.ID, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return spiffeid.ID{}, errors.New("缺少调用方信息")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return spiffeid.ID{}, errors.New("调用方未提供 X.509 SVID")
+	}
+	return x509svid.IDFromCert(tlsInfo.State.PeerCertificates[0])
+}
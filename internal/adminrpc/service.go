@@ -0,0 +1,334 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package adminrpc 提供防火墙 NSE 的管理 gRPC 接口
+//
+// 接口监听在独立的本地 socket 上，使用 SPIFFE X.509 SVID 进行 mTLS 认证，
+// 只允许配置的 SPIFFE ID 调用。
+package adminrpc
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+)
+
+// serviceName gRPC 服务名称
+const serviceName = "firewall.admin.v1.FirewallAdmin"
+
+// 方法名称
+const (
+	listConnectionsMethod    = "ListConnections"
+	getConnectionRulesMethod = "GetConnectionRules"
+	listRuleSetsMethod       = "ListRuleSets"
+	addRuleMethod            = "AddRule"
+	removeRuleMethod         = "RemoveRule"
+	moveRuleMethod           = "MoveRule"
+	replaceRuleMethod        = "ReplaceRule"
+	listTemporaryRulesMethod = "ListTemporaryRules"
+	quarantineMethod         = "QuarantineConnection"
+	releaseMethod            = "ReleaseConnection"
+	getEmergencyModeMethod   = "GetEmergencyMode"
+	setEmergencyModeMethod   = "SetEmergencyMode"
+)
+
+// ListConnectionsRequest 列出连接的请求
+type ListConnectionsRequest struct{}
+
+// ListConnectionsResponse 列出连接的响应
+type ListConnectionsResponse struct {
+	Connections []acl.ConnectionInfo `json:"connections"`
+}
+
+// GetConnectionRulesRequest 查询连接规则的请求
+type GetConnectionRulesRequest struct {
+	ConnectionID string `json:"connectionId"`
+}
+
+// ConnectionRequest 隔离或解除隔离连接的请求
+type ConnectionRequest struct {
+	ConnectionID string `json:"connectionId"`
+}
+
+// GetEmergencyModeRequest 查询紧急模式的请求
+type GetEmergencyModeRequest struct{}
+
+// SetEmergencyModeRequest 切换紧急模式的请求，Mode 为 off、deny-all 或 allow-all
+type SetEmergencyModeRequest struct {
+	Mode acl.EmergencyMode `json:"mode"`
+}
+
+// ListRuleSetsRequest 列出规则集的请求
+type ListRuleSetsRequest struct{}
+
+// ListRuleSetsResponse 列出规则集的响应
+type ListRuleSetsResponse struct {
+	RuleSets []acl.RuleSetInfo `json:"ruleSets"`
+}
+
+// AddRuleRequest 添加规则的请求
+//
+// Rule 为规则内容，格式与配置文件中一条规则的内容相同；Position 为插入位置（0 为最前面），未设置时追加到末尾。
+// TTL 为临时规则的有效期（如 "30m"），设置时规则排在全部配置的规则之前匹配，到期后自动删除，不能同时设置 Position。
+type AddRuleRequest struct {
+	RuleSet  string          `json:"ruleSet"`
+	Name     string          `json:"name"`
+	Position *int            `json:"position,omitempty"`
+	TTL      string          `json:"ttl,omitempty"`
+	Rule     json.RawMessage `json:"rule"`
+}
+
+// RemoveRuleRequest 删除规则的请求
+type RemoveRuleRequest struct {
+	RuleSet string `json:"ruleSet"`
+	Name    string `json:"name"`
+}
+
+// MoveRuleRequest 移动规则的请求
+type MoveRuleRequest struct {
+	RuleSet  string `json:"ruleSet"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+// ReplaceRuleRequest 替换规则内容的请求
+type ReplaceRuleRequest struct {
+	RuleSet string          `json:"ruleSet"`
+	Name    string          `json:"name"`
+	Rule    json.RawMessage `json:"rule"`
+}
+
+// ListTemporaryRulesRequest 列出临时规则的请求
+type ListTemporaryRulesRequest struct{}
+
+// ListTemporaryRulesResponse 列出临时规则的响应
+type ListTemporaryRulesResponse struct {
+	Rules []acl.TemporaryRuleInfo `json:"rules"`
+}
+
+// adminServer 管理接口实现
+type adminServer struct {
+	controller *acl.Controller
+}
+
+// ListConnections 列出全部连接及其 ACL 状态
+func (s *adminServer) ListConnections(_ context.Context, _ *ListConnectionsRequest) (*ListConnectionsResponse, error) {
+	return &ListConnectionsResponse{Connections: s.controller.Connections()}, nil
+}
+
+// GetConnectionRules 返回连接已下发的完整规则
+func (s *adminServer) GetConnectionRules(_ context.Context, req *GetConnectionRulesRequest) (*acl.ConnectionRules, error) {
+	if req.ConnectionID == "" {
+		return nil, status.Error(codes.InvalidArgument, "缺少连接 ID")
+	}
+	rules, ok := s.controller.ConnectionRules(req.ConnectionID)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "连接 %s 不存在", req.ConnectionID)
+	}
+	return rules, nil
+}
+
+// QuarantineConnection 隔离连接，返回隔离后的连接状态
+func (s *adminServer) QuarantineConnection(ctx context.Context, req *ConnectionRequest) (*acl.ConnectionInfo, error) {
+	if req.ConnectionID == "" {
+		return nil, status.Error(codes.InvalidArgument, "缺少连接 ID")
+	}
+	info, err := s.controller.Quarantine(ctx, req.ConnectionID)
+	return info, ruleError(err)
+}
+
+// ReleaseConnection 解除连接的隔离，返回解除后的连接状态
+func (s *adminServer) ReleaseConnection(ctx context.Context, req *ConnectionRequest) (*acl.ConnectionInfo, error) {
+	if req.ConnectionID == "" {
+		return nil, status.Error(codes.InvalidArgument, "缺少连接 ID")
+	}
+	info, err := s.controller.Release(ctx, req.ConnectionID)
+	return info, ruleError(err)
+}
+
+// GetEmergencyMode 返回全局紧急模式状态
+func (s *adminServer) GetEmergencyMode(_ context.Context, _ *GetEmergencyModeRequest) (*acl.EmergencyStatus, error) {
+	rv := s.controller.EmergencyStatus()
+	return &rv, nil
+}
+
+// SetEmergencyMode 切换全局紧急模式
+func (s *adminServer) SetEmergencyMode(ctx context.Context, req *SetEmergencyModeRequest) (*acl.EmergencyStatus, error) {
+	mode, err := acl.ParseEmergencyMode(string(req.Mode))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	rv, err := s.controller.SetEmergencyMode(ctx, mode)
+	return rv, ruleError(err)
+}
+
+// ListRuleSets 列出配置的规则集及其规则
+func (s *adminServer) ListRuleSets(_ context.Context, _ *ListRuleSetsRequest) (*ListRuleSetsResponse, error) {
+	return &ListRuleSetsResponse{RuleSets: s.controller.RuleSets()}, nil
+}
+
+// AddRule 向规则集添加规则，设置了 TTL 时添加临时规则
+func (s *adminServer) AddRule(ctx context.Context, req *AddRuleRequest) (*acl.RuleSetInfo, error) {
+	if req.TTL != "" {
+		if req.Position != nil {
+			return nil, status.Error(codes.InvalidArgument, "临时规则不能指定位置")
+		}
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "有效期 %q 无效: %v", req.TTL, err)
+		}
+		set, err := s.controller.AddTemporaryRule(ctx, req.RuleSet, req.Name, req.Rule, ttl)
+		return set, ruleError(err)
+	}
+	position := -1
+	if req.Position != nil {
+		if *req.Position < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "位置 %d 无效", *req.Position)
+		}
+		position = *req.Position
+	}
+	set, err := s.controller.AddRule(ctx, req.RuleSet, position, req.Name, req.Rule)
+	return set, ruleError(err)
+}
+
+// RemoveRule 从规则集删除规则
+func (s *adminServer) RemoveRule(ctx context.Context, req *RemoveRuleRequest) (*acl.RuleSetInfo, error) {
+	set, err := s.controller.RemoveRule(ctx, req.RuleSet, req.Name)
+	return set, ruleError(err)
+}
+
+// MoveRule 移动规则在规则集中的位置
+func (s *adminServer) MoveRule(ctx context.Context, req *MoveRuleRequest) (*acl.RuleSetInfo, error) {
+	set, err := s.controller.MoveRule(ctx, req.RuleSet, req.Name, req.Position)
+	return set, ruleError(err)
+}
+
+// ReplaceRule 替换规则集中规则的内容
+func (s *adminServer) ReplaceRule(ctx context.Context, req *ReplaceRuleRequest) (*acl.RuleSetInfo, error) {
+	set, err := s.controller.ReplaceRule(ctx, req.RuleSet, req.Name, req.Rule)
+	return set, ruleError(err)
+}
+
+// ListTemporaryRules 列出临时规则及其剩余有效期
+func (s *adminServer) ListTemporaryRules(_ context.Context, _ *ListTemporaryRulesRequest) (*ListTemporaryRulesResponse, error) {
+	return &ListTemporaryRulesResponse{Rules: s.controller.TemporaryRules()}, nil
+}
+
+// ruleError 将修改规则或连接的错误转换为 gRPC 状态
+func ruleError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, acl.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, acl.ErrInvalidRule):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, acl.ErrReadOnly):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// serviceDesc 管理接口的 gRPC 服务描述
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: listConnectionsMethod,
+			Handler:    unaryHandler(listConnectionsMethod, (*adminServer).ListConnections),
+		},
+		{
+			MethodName: getConnectionRulesMethod,
+			Handler:    unaryHandler(getConnectionRulesMethod, (*adminServer).GetConnectionRules),
+		},
+		{
+			MethodName: quarantineMethod,
+			Handler:    unaryHandler(quarantineMethod, (*adminServer).QuarantineConnection),
+		},
+		{
+			MethodName: releaseMethod,
+			Handler:    unaryHandler(releaseMethod, (*adminServer).ReleaseConnection),
+		},
+		{
+			MethodName: getEmergencyModeMethod,
+			Handler:    unaryHandler(getEmergencyModeMethod, (*adminServer).GetEmergencyMode),
+		},
+		{
+			MethodName: setEmergencyModeMethod,
+			Handler:    unaryHandler(setEmergencyModeMethod, (*adminServer).SetEmergencyMode),
+		},
+		{
+			MethodName: listRuleSetsMethod,
+			Handler:    unaryHandler(listRuleSetsMethod, (*adminServer).ListRuleSets),
+		},
+		{
+			MethodName: addRuleMethod,
+			Handler:    unaryHandler(addRuleMethod, (*adminServer).AddRule),
+		},
+		{
+			MethodName: removeRuleMethod,
+			Handler:    unaryHandler(removeRuleMethod, (*adminServer).RemoveRule),
+		},
+		{
+			MethodName: moveRuleMethod,
+			Handler:    unaryHandler(moveRuleMethod, (*adminServer).MoveRule),
+		},
+		{
+			MethodName: replaceRuleMethod,
+			Handler:    unaryHandler(replaceRuleMethod, (*adminServer).ReplaceRule),
+		},
+		{
+			MethodName: listTemporaryRulesMethod,
+			Handler:    unaryHandler(listTemporaryRulesMethod, (*adminServer).ListTemporaryRules),
+		},
+	},
+	Metadata: "adminrpc",
+}
+
+// unaryHandler 将 adminServer 的方法包装为 gRPC 一元方法处理器
+func unaryHandler[Req, Resp any](method string, fn func(*adminServer, context.Context, *Req) (*Resp, error)) grpc.MethodHandler {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		req := new(Req)
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+		s := srv.(*adminServer)
+		if interceptor == nil {
+			return fn(s, ctx, req)
+		}
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod(method)}
+		return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return fn(s, ctx, req.(*Req))
+		})
+	}
+}
+
+// fullMethod 返回方法的完整名称
+func fullMethod(method string) string {
+	return "/" + serviceName + "/" + method
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package audit 记录防火墙策略相关的审计事件
+//
+// 事件以 WARN 级别写入日志（带 audit 字段，便于日志系统过滤），并在内存中保留最近的
+// 事件，供管理端点查询。
+package audit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// maxEvents 内存中保留的事件数
+const maxEvents = 256
+
+// 事件类型
+const (
+	// PolicyRejected 策略未通过签名校验或规则校验，未生效
+	PolicyRejected = "policy-rejected"
+)
+
+// Event 审计事件
+//
+// 字段说明:
+//   - Time: 事件时间
+//   - Type: 事件类型
+//   - Source: 事件来源（如配置文件路径、策略服务器地址）
+//   - Message: 事件详情
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Source  string    `json:"source"`
+	Message string    `json:"message"`
+}
+
+var (
+	mu     sync.Mutex
+	events []Event
+)
+
+// Record 记录审计事件
+func Record(ctx context.Context, eventType, source, message string) {
+	event := Event{Time: time.Now(), Type: eventType, Source: source, Message: message}
+	log.FromContext(ctx).
+		WithField("audit", eventType).
+		WithField("source", source).
+		Warnf("审计事件: %s", message)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) == maxEvents {
+		events = append(events[:0], events[1:]...)
+	}
+	events = append(events, event)
+}
+
+// Events 返回内存中保留的审计事件，按时间先后排序
+func Events() []Event {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Event{}, events...)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package blocklist 从本地文件加载 IP 黑名单，编译为优先于其他规则的 ACL 拒绝规则
+package blocklist
+
+import (
+	"context"
+	"net/netip"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/fs"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+)
+
+// ruleName 黑名单拒绝规则的名称
+const ruleName = "blocklist"
+
+// FeedStats 单个黑名单文件的统计信息
+//
+// 字段说明:
+//   - Feed: 文件路径
+//   - Entries: 有效条目数
+//   - Invalid: 无法解析的行数
+//   - Contributed: 未被排在前面的文件包含的条目数，即该文件新增的条目
+//   - LoadedAt: 最近一次加载时间，文件不存在时为零值
+type FeedStats struct {
+	Feed        string    `json:"feed"`
+	Entries     int       `json:"entries"`
+	Invalid     int       `json:"invalid"`
+	Contributed int       `json:"contributed"`
+	LoadedAt    time.Time `json:"loadedAt"`
+}
+
+// Stats 全部黑名单的统计信息
+//
+// Prefixes 为去重聚合后下发的拒绝规则数。
+type Stats struct {
+	Feeds    []FeedStats `json:"feeds"`
+	Prefixes int         `json:"prefixes"`
+}
+
+// feed 一个黑名单文件最近一次加载的内容
+type feed struct {
+	path     string
+	prefixes []netip.Prefix
+	invalid  int
+	loadedAt time.Time
+}
+
+// Blocklists 监听黑名单文件，将其内容同步到防火墙策略
+//
+// mu 保护文件内容和统计信息；updateMu 串行化策略更新，保证策略中是最新的黑名单。
+type Blocklists struct {
+	mu       sync.Mutex
+	updateMu sync.Mutex
+	policy   *acl.Policy
+	feeds    []*feed
+	stats    Stats
+	applied  []netip.Prefix
+}
+
+// Watch 加载黑名单文件并监听变化
+//
+// 功能说明:
+//   - 同步加载每个文件的当前内容并更新策略，返回时黑名单规则已生效
+//   - 之后文件每次写入或重新创建都会重新加载并更新策略
+//   - 文件被删除时保留最近一次加载的内容，避免黑名单短暂失效
+//   - 各文件的条目合并、去重并聚合后，编译为拒绝规则放在其他规则之前
+//
+// 参数:
+//   - ctx: 上下文，结束时停止监听
+//   - policy: 防火墙策略
+//   - files: 黑名单文件路径列表
+//
+// 返回:
+//   - *Blocklists: 黑名单，可查询统计信息
+func Watch(ctx context.Context, policy *acl.Policy, files []string) *Blocklists {
+	b := &Blocklists{policy: policy}
+	for _, path := range files {
+		f := &feed{path: path}
+		b.feeds = append(b.feeds, f)
+
+		ch := fs.WatchFile(ctx, path)
+		b.load(ctx, f, <-ch)
+		go func() {
+			for data := range ch {
+				b.load(ctx, f, data)
+				b.update(ctx)
+			}
+		}()
+	}
+	b.update(ctx)
+	return b
+}
+
+// Stats 返回黑名单的统计信息
+func (b *Blocklists) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return Stats{
+		Feeds:    append([]FeedStats{}, b.stats.Feeds...),
+		Prefixes: b.stats.Prefixes,
+	}
+}
+
+// load 解析文件内容，data 为 nil 表示文件不存在或已被删除
+func (b *Blocklists) load(ctx context.Context, f *feed, data []byte) {
+	logger := log.FromContext(ctx).WithField("blocklist", f.path)
+	if data == nil {
+		logger.Warn("黑名单文件不存在，保留最近一次加载的内容")
+		return
+	}
+	prefixes, invalid := parseFeed(data)
+	if invalid > 0 {
+		logger.Warnf("忽略 %d 行无效的黑名单条目", invalid)
+	}
+	logger.Infof("加载黑名单条目 %d 条", len(prefixes))
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	f.prefixes, f.invalid, f.loadedAt = prefixes, invalid, time.Now()
+}
+
+// update 合并全部文件的条目，重新计算统计信息并更新策略
+//
+// 聚合后的前缀没有变化时（如文件被删除，或只修改了注释）不更新策略，避免重新下发 ACL。
+func (b *Blocklists) update(ctx context.Context) {
+	b.updateMu.Lock()
+	defer b.updateMu.Unlock()
+
+	b.mu.Lock()
+	var all []netip.Prefix
+	stats := Stats{Feeds: make([]FeedStats, 0, len(b.feeds))}
+	for _, f := range b.feeds {
+		earlier := aggregate(all)
+		contributed := 0
+		for _, p := range f.prefixes {
+			if !covered(earlier, p) {
+				contributed++
+			}
+		}
+		all = append(all, f.prefixes...)
+		stats.Feeds = append(stats.Feeds, FeedStats{
+			Feed:        f.path,
+			Entries:     len(f.prefixes),
+			Invalid:     f.invalid,
+			Contributed: contributed,
+			LoadedAt:    f.loadedAt,
+		})
+	}
+	aggregated := append([]netip.Prefix{}, aggregate(all)...)
+	stats.Prefixes = len(aggregated)
+	b.stats = stats
+	b.mu.Unlock()
+
+	if b.applied != nil && slices.Equal(b.applied, aggregated) {
+		return
+	}
+	b.applied = aggregated
+
+	rules := make([]acl.Rule, 0, len(aggregated))
+	for _, p := range aggregated {
+		rule, err := acl.DenyRule(ruleName, p)
+		if err != nil {
+			log.FromContext(ctx).WithField("blocklist", "update").Errorf("忽略黑名单前缀 %v: %v", p, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	b.policy.SetBlocklist(ctx, rules)
+}
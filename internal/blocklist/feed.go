@@ -0,0 +1,143 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package blocklist
+
+import (
+	"bufio"
+	"bytes"
+	"net/netip"
+	"sort"
+	"strings"
+)
+
+// parseFeed 解析纯文本黑名单
+//
+// 功能说明:
+//   - 每行一个 CIDR 前缀或单个 IP 地址（视为 /32 或 /128）
+//   - 忽略空行；"#" 之后的内容为注释
+//   - 无法解析的行计入 invalid，不影响其余条目
+//
+// 参数:
+//   - data: 黑名单文件内容
+//
+// 返回:
+//   - prefixes: 解析出的前缀列表（已按掩码规整）
+//   - invalid: 无效行数
+func parseFeed(data []byte) (prefixes []netip.Prefix, invalid int) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		prefix, err := parsePrefix(line)
+		if err != nil {
+			invalid++
+			continue
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, invalid
+}
+
+// parsePrefix 解析 CIDR 前缀或单个 IP 地址，IPv4 映射的 IPv6 地址按 IPv4 处理
+func parsePrefix(s string) (netip.Prefix, error) {
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	if addr := prefix.Addr(); addr.Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(addr.Unmap(), prefix.Bits()-96)
+	}
+	return prefix.Masked(), nil
+}
+
+// aggregate 去重并聚合前缀列表
+//
+// 功能说明:
+//   - 去掉被其他前缀包含的前缀
+//   - 合并相邻的兄弟前缀（如 10.0.0.0/25 和 10.0.0.128/25 合并为 10.0.0.0/24）
+//   - 返回的前缀按地址排序且互不重叠，IPv4 在 IPv6 之前
+func aggregate(prefixes []netip.Prefix) []netip.Prefix {
+	sorted := append([]netip.Prefix{}, prefixes...)
+	sortPrefixes(sorted)
+
+	rv := make([]netip.Prefix, 0, len(sorted))
+	for _, p := range sorted {
+		// 排序后包含 p 的前缀一定在 p 之前，且与 p 之间没有其他保留的前缀
+		if n := len(rv); n > 0 && rv[n-1].Bits() <= p.Bits() && rv[n-1].Contains(p.Addr()) {
+			continue
+		}
+		rv = append(rv, p)
+		for n := len(rv); n >= 2; n = len(rv) {
+			parent, ok := mergeSiblings(rv[n-2], rv[n-1])
+			if !ok {
+				break
+			}
+			rv = append(rv[:n-2], parent)
+		}
+	}
+	return rv
+}
+
+// mergeSiblings 如果 a 和 b 是同一前缀的两半，返回该前缀
+func mergeSiblings(a, b netip.Prefix) (netip.Prefix, bool) {
+	if a.Bits() != b.Bits() || a.Bits() == 0 || a.Addr().BitLen() != b.Addr().BitLen() || a == b {
+		return netip.Prefix{}, false
+	}
+	parentA, _ := a.Addr().Prefix(a.Bits() - 1)
+	parentB, _ := b.Addr().Prefix(b.Bits() - 1)
+	return parentA, parentA == parentB
+}
+
+// covered 判断 p 是否被 aggregated 中的某个前缀包含，aggregated 需为 aggregate 的结果
+func covered(aggregated []netip.Prefix, p netip.Prefix) bool {
+	// 前缀互不重叠，只有起始地址不大于 p 的最后一个前缀可能包含 p
+	i := sort.Search(len(aggregated), func(i int) bool {
+		return aggregated[i].Addr().Compare(p.Addr()) > 0
+	})
+	if i == 0 {
+		return false
+	}
+	q := aggregated[i-1]
+	return q.Bits() <= p.Bits() && q.Contains(p.Addr())
+}
+
+// sortPrefixes 按地址排序，地址相同时掩码短的在前
+func sortPrefixes(prefixes []netip.Prefix) {
+	sort.Slice(prefixes, func(i, j int) bool {
+		if c := prefixes[i].Addr().Compare(prefixes[j].Addr()); c != 0 {
+			return c < 0
+		}
+		return prefixes[i].Bits() < prefixes[j].Bits()
+	})
+}
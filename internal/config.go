@@ -28,54 +28,148 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
-	"github.com/networkservicemesh/govpp/binapi/acl_types"
 	"github.com/networkservicemesh/sdk/pkg/tools/log"
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+	"github.com/ifzzh/cmd-nse-template/internal/audit"
+	"github.com/ifzzh/cmd-nse-template/internal/signature"
 )
 
 // Config 保存从环境变量读取的配置参数
 type Config struct {
-	Name                   string              `default:"firewall-server" desc:"Name of Firewall Server"`
-	ListenOn               string              `default:"listen.on.sock" desc:"listen on socket" split_words:"true"`
-	ConnectTo              url.URL             `default:"unix:///var/lib/networkservicemesh/nsm.io.sock" desc:"url to connect to" split_words:"true"`
-	MaxTokenLifetime       time.Duration       `default:"10m" desc:"maximum lifetime of tokens" split_words:"true"`
-	RegistryClientPolicies []string            `default:"etc/nsm/opa/common/.*.rego,etc/nsm/opa/registry/.*.rego,etc/nsm/opa/client/.*.rego" desc:"paths to files and directories that contain registry client policies" split_words:"true"`
-	ServiceName            string              `default:"" desc:"Name of providing service" split_words:"true"`
-	Labels                 map[string]string   `default:"" desc:"Endpoint labels"`
-	ACLConfigPath          string              `default:"/etc/firewall/config.yaml" desc:"Path to ACL config file" split_words:"true"`
-	ACLConfig              []acl_types.ACLRule `default:"" desc:"configured acl rules" split_words:"true"`
-	LogLevel               string              `default:"INFO" desc:"Log level" split_words:"true"`
-	OpenTelemetryEndpoint  string              `default:"otel-collector.observability.svc.cluster.local:4317" desc:"OpenTelemetry Collector Endpoint" split_words:"true"`
-	MetricsExportInterval  time.Duration       `default:"10s" desc:"interval between mertics exports" split_words:"true"`
-	PprofEnabled           bool                `default:"false" desc:"is pprof enabled" split_words:"true"`
-	PprofListenOn          string              `default:"localhost:6060" desc:"pprof URL to ListenAndServe" split_words:"true"`
+	Name                   string            `default:"firewall-server" desc:"Name of Firewall Server"`
+	ListenOn               string            `default:"listen.on.sock" desc:"listen on socket" split_words:"true"`
+	ConnectTo              url.URL           `default:"unix:///var/lib/networkservicemesh/nsm.io.sock" desc:"url to connect to" split_words:"true"`
+	MaxTokenLifetime       time.Duration     `default:"10m" desc:"maximum lifetime of tokens" split_words:"true"`
+	RegistryClientPolicies []string          `default:"etc/nsm/opa/common/.*.rego,etc/nsm/opa/registry/.*.rego,etc/nsm/opa/client/.*.rego" desc:"paths to files and directories that contain registry client policies" split_words:"true"`
+	ServiceName            string            `default:"" desc:"Name of providing service" split_words:"true"`
+	ServicesConfigPath     string            `default:"" desc:"path of a YAML file declaring the network services provided by the endpoint with their own labels, rule sets and next hop, replaces NSM_SERVICE_NAME" split_words:"true"`
+	Labels                 map[string]string `default:"" desc:"Endpoint labels"`
+	RegistrationTTL        time.Duration     `default:"1m" desc:"lifetime of the endpoint registration, refreshed at a third of the remaining lifetime" split_words:"true"`
+	RegistrationBackoff    time.Duration     `default:"20s" desc:"maximum backoff between re-registration attempts after registry errors" split_words:"true"`
+	ShutdownTimeout        time.Duration     `default:"30s" desc:"deadline for unregistering the endpoint and closing active connections on shutdown" split_words:"true"`
+	ACLConfigPath          string            `default:"/etc/firewall/config.yaml" desc:"Path to ACL config file" split_words:"true"`
+	ACLConfig              []acl.Rule        `default:"" desc:"configured acl rules" split_words:"true"`
+	ACLStatePath           string            `default:"/var/lib/firewall/rules-state.yaml" desc:"path of the state file that keeps rules changed at runtime through the admin gRPC API across restarts" split_words:"true"`
+	ACLRetryMaxAttempts    int               `default:"3" desc:"maximum attempts of ACL programming on transient VPP API failures" split_words:"true"`
+	ACLRetryBackoff        time.Duration     `default:"100ms" desc:"initial backoff between ACL programming attempts" split_words:"true"`
+	ACLRetryMaxBackoff     time.Duration     `default:"2s" desc:"maximum backoff between ACL programming attempts" split_words:"true"`
+	ACLRetryCallTimeout    time.Duration     `default:"5s" desc:"upper bound of a single VPP ACL API call timeout" split_words:"true"`
+	ACLFailOpenRetry       time.Duration     `default:"5s" desc:"interval between background ACL programming retries of fail-open (degraded) connections" split_words:"true"`
+	ACLPosition            acl.Position      `default:"prepend" desc:"position of the firewall ACLs among other ACLs bound to the interface (prepend or append)" split_words:"true"`
+	ACLChunkSize           int               `default:"1024" desc:"maximum number of rules per ACL, larger rule sets are split across several ACLs" split_words:"true"`
+	ACLPolicyURL           string            `default:"" desc:"HTTP(S) URL of a policy server to poll for the ACL rule set, replaces the rules from the config file" split_words:"true"`
+	ACLPolicyPollInterval  time.Duration     `default:"30s" desc:"interval between policy server polls" split_words:"true"`
+	ACLPolicyTimeout       time.Duration     `default:"10s" desc:"timeout of a single policy server request" split_words:"true"`
+	ACLPolicyCachePath     string            `default:"/var/lib/firewall/policy.yaml" desc:"path of the last good policy fetched from the policy server, used when the server is unreachable" split_words:"true"`
+	ACLPolicyTrustedKeys   []string          `default:"" desc:"comma-separated list of PEM public key files trusted to sign policy bundles, enables signature verification" split_words:"true"`
+	ACLPolicyTrustRoots    []string          `default:"" desc:"comma-separated list of PEM CA certificate files trusted to issue policy signing certificates, enables signature verification" split_words:"true"`
+	ACLBlocklistFiles      []string          `default:"" desc:"comma-separated list of IP blocklist files (one CIDR or address per line), compiled into deny rules ahead of other rules" split_words:"true"`
+	ACLQuarantineRuleSet   string            `default:"" desc:"name of the rule set applied to quarantined connections instead of the policy, excluded from other connections (default: deny all)" split_words:"true"`
+	ACLAntiSpoof           bool              `default:"false" desc:"prepend deny rules for sources outside the connection's assigned addresses" split_words:"true"`
+	MACIPEnabled           bool              `default:"false" desc:"bind NSC source MAC/IP with MACIP ACLs to block address spoofing" split_words:"true"`
+	ACLCountersEnabled     bool              `default:"true" desc:"enable per-rule ACL hit counters" split_words:"true"`
+	ACLCountersInterval    time.Duration     `default:"10s" desc:"interval between ACL hit counter reads" split_words:"true"`
+	ACLDeadRuleWindow      time.Duration     `default:"24h" desc:"default time window of the dead-rule report" split_words:"true"`
+	VPPStatsSocket         string            `default:"/var/run/vpp/stats.sock" desc:"Path to VPP stats segment socket" split_words:"true"`
+	LogLevel               string            `default:"INFO" desc:"Log level" split_words:"true"`
+	OpenTelemetryEndpoint  string            `default:"otel-collector.observability.svc.cluster.local:4317" desc:"OpenTelemetry Collector Endpoint" split_words:"true"`
+	MetricsExportInterval  time.Duration     `default:"10s" desc:"interval between mertics exports" split_words:"true"`
+	PprofEnabled           bool              `default:"false" desc:"is pprof enabled" split_words:"true"`
+	PprofListenOn          string            `default:"localhost:6060" desc:"pprof URL to ListenAndServe" split_words:"true"`
+	AdminEnabled           bool              `default:"false" desc:"is admin HTTP endpoint enabled" split_words:"true"`
+	AdminListenOn          string            `default:"localhost:9090" desc:"admin HTTP endpoint URL to ListenAndServe" split_words:"true"`
+	AdminGRPCEnabled       bool              `default:"false" desc:"is admin gRPC API enabled" split_words:"true"`
+	AdminGRPCListenOn      url.URL           `default:"unix:///var/lib/firewall/admin.sock" desc:"local socket of the admin gRPC API" split_words:"true"`
+	AdminGRPCAllowedIDs    []string          `default:"" desc:"comma-separated list of SPIFFE IDs allowed to call the admin gRPC API (default: the firewall's own SPIFFE ID)" split_words:"true"`
+	HealthEnabled          bool              `default:"false" desc:"are HTTP health endpoint (/healthz, /readyz) and gRPC health service enabled" split_words:"true"`
+	HealthListenOn         string            `default:":8080" desc:"HTTP health endpoint URL to ListenAndServe" split_words:"true"`
+	HealthGRPCListenOn     string            `default:":8081" desc:"plaintext TCP address of the gRPC health service (grpc.health.v1)" split_words:"true"`
+	HealthReconcileTimeout time.Duration     `default:"2m" desc:"duration of a running policy reconcile after which the liveness check fails" split_words:"true"`
+
+	// PolicyVerifier 策略签名校验器，由 ACLPolicyTrustedKeys 和 ACLPolicyTrustRoots 构建，未配置时为 nil
+	PolicyVerifier *signature.Verifier `ignored:"true"`
+	// ACLRuleSets 配置的规则集（配置文件中的规则集，或状态文件中运行时修改后的规则集），ACLConfig 为其展开后的规则
+	ACLRuleSets []acl.RuleSet `ignored:"true"`
+	// ACLTemporaryRules 状态文件中尚未到期的临时规则
+	ACLTemporaryRules []acl.TemporaryRule `ignored:"true"`
+	// ACLConfigDigest 配置文件内容的摘要，状态文件据此判断配置文件是否已变化
+	ACLConfigDigest string `ignored:"true"`
+	// Services 端点提供的网络服务，由 ServicesConfigPath 读取，未配置时为 ServiceName 一个网络服务
+	Services []ServiceConfig `ignored:"true"`
 }
 
 // LoadConfig 从环境变量加载配置并解析ACL规则
 // 返回完整初始化的Config对象，如果配置加载失败则返回error
 func LoadConfig(ctx context.Context) (*Config, error) {
-	config := new(Config)
-
 	// 显示配置用法
-	if err := envconfig.Usage("nsm", config); err != nil {
+	if err := envconfig.Usage("nsm", new(Config)); err != nil {
 		return nil, errors.Wrap(err, "cannot show usage of envconfig nsm")
 	}
 
+	return ReloadConfig(ctx)
+}
+
+// ReloadConfig 重新从环境变量加载配置并解析ACL规则，与 LoadConfig 相同但不显示配置用法
+// 用于运行中重新加载配置（SIGHUP）
+func ReloadConfig(ctx context.Context) (*Config, error) {
+	config := new(Config)
+
 	// 从环境变量解析配置
 	if err := envconfig.Process("nsm", config); err != nil {
 		return nil, errors.Wrap(err, "cannot process envconfig nsm")
 	}
 
+	// 加载策略签名的公钥和信任根
+	verifier, err := signature.NewVerifier(config.ACLPolicyTrustedKeys, config.ACLPolicyTrustRoots)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot load policy signature trust")
+	}
+	config.PolicyVerifier = verifier
+
+	// 加载端点提供的网络服务
+	if err := retrieveServices(config); err != nil {
+		return nil, err
+	}
+
 	// 加载ACL规则
 	retrieveACLRules(ctx, config)
+	checkServiceRuleSets(ctx, config)
 
 	return config, nil
 }
 
+// derivedFields 由ACL配置文件和策略签名信任文件派生的字段，不参与 ChangedFields 比较
+var derivedFields = map[string]bool{
+	"ACLConfig":         true,
+	"ACLRuleSets":       true,
+	"ACLTemporaryRules": true,
+	"ACLConfigDigest":   true,
+	"PolicyVerifier":    true,
+}
+
+// ChangedFields 返回 c 与 other 取值不同的配置项名称，按字段顺序排列
+// 由ACL配置文件派生的字段不参与比较，配置文件的变化通过 ACLConfigDigest 判断
+func (c *Config) ChangedFields(other *Config) []string {
+	var changed []string
+	current, next := reflect.ValueOf(c).Elem(), reflect.ValueOf(other).Elem()
+	for i := 0; i < current.NumField(); i++ {
+		field := current.Type().Field(i)
+		if derivedFields[field.Name] {
+			continue
+		}
+		if !reflect.DeepEqual(current.Field(i).Interface(), next.Field(i).Interface()) {
+			changed = append(changed, field.Name)
+		}
+	}
+	return changed
+}
+
 // retrieveACLRules 从配置文件读取ACL规则并添加到Config中
 // 如果文件读取或解析失败，记录错误但不中断程序运行
 func retrieveACLRules(ctx context.Context, c *Config) {
@@ -88,17 +182,59 @@ func retrieveACLRules(ctx context.Context, c *Config) {
 	}
 	logger.Infof("Read config file successfully")
 
-	var rv map[string]acl_types.ACLRule
-	err = yaml.Unmarshal(raw, &rv)
+	// 配置了签名校验时，配置文件须有同名 .sig 分离签名，校验失败的规则不生效
+	if c.PolicyVerifier != nil {
+		sig, sigErr := os.ReadFile(filepath.Clean(c.ACLConfigPath + signature.Suffix))
+		if sigErr == nil {
+			sigErr = c.PolicyVerifier.Verify(raw, sig)
+		}
+		if sigErr != nil {
+			audit.Record(ctx, audit.PolicyRejected, c.ACLConfigPath, "配置文件签名校验失败: "+sigErr.Error())
+			return
+		}
+		logger.Infof("Verified config file signature successfully")
+	}
+
+	sets, err := acl.ParseRuleSets(raw)
 	if err != nil {
 		logger.Errorf("Error parsing config file: %v", err)
+		audit.Record(ctx, audit.PolicyRejected, c.ACLConfigPath, "配置文件规则校验失败: "+err.Error())
 		return
 	}
 	logger.Infof("Parsed acl rules successfully")
+	c.ACLConfigDigest = acl.ConfigDigest(raw)
 
-	for _, v := range rv {
-		c.ACLConfig = append(c.ACLConfig, v)
+	// 恢复通过管理接口在运行时修改的规则；配置文件在此之后有变化时以配置文件为准。
+	// 配置了签名校验时只使用签名校验通过的配置文件，不加载未签名的状态文件
+	if c.PolicyVerifier == nil {
+		state, stateErr := acl.LoadState(c.ACLStatePath, c.ACLConfigDigest)
+		switch {
+		case stateErr != nil:
+			logger.Warnf("Ignoring rules state file %s: %v", c.ACLStatePath, stateErr)
+		case state != nil:
+			logger.Infof("Restored runtime rule changes from %s", c.ACLStatePath)
+			sets = state.RuleSets
+			c.ACLTemporaryRules = state.Temporary
+		}
 	}
 
+	c.ACLRuleSets = sets
+	c.ACLConfig = append(c.ACLConfig, acl.Flatten(sets)...)
+
 	logger.Infof("Result rules:%v", c.ACLConfig)
 }
+
+// checkServiceRuleSets 对网络服务引用了配置中不存在的规则集记录警告，在策略中出现该规则集之前这些网络服务的连接拒绝全部流量
+func checkServiceRuleSets(ctx context.Context, c *Config) {
+	known := make(map[string]bool, len(c.ACLRuleSets))
+	for i := range c.ACLRuleSets {
+		known[c.ACLRuleSets[i].Name] = true
+	}
+	for _, service := range c.Services {
+		for _, name := range service.RuleSets {
+			if !known[name] {
+				log.FromContext(ctx).WithField("acl", "config").Warnf("Service %q refers to unknown rule set %q, its connections deny all traffic", service.Name, name)
+			}
+		}
+	}
+}
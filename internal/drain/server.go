@@ -0,0 +1,141 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package drain 提供关闭端点时停止接受新连接并关闭全部活动连接的链式元素
+package drain
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/begin"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// Server 跟踪活动连接的链式元素
+//
+// 字段说明:
+//   - mu: 保护 draining 和 conns
+//   - draining: 是否已开始关闭，关闭后拒绝全部 Request
+//   - conns: 连接 ID 到连接事件工厂的映射，关闭时通过事件工厂经完整链关闭连接
+//   - inflight: 处理中的 Request
+type Server struct {
+	mu       sync.Mutex
+	draining bool
+	conns    map[string]begin.EventFactory
+	inflight sync.WaitGroup
+}
+
+// NewServer 创建活动连接跟踪链式元素，须位于 begin 之后（如端点的附加功能链中）
+func NewServer() *Server {
+	return &Server{conns: make(map[string]begin.EventFactory)}
+}
+
+// Request 处理网络服务请求，开始关闭后返回 Unavailable
+func (s *Server) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.mu.Lock()
+	if s.draining {
+		s.mu.Unlock()
+		return nil, status.Error(codes.Unavailable, "端点正在关闭，不再接受请求")
+	}
+	s.inflight.Add(1)
+	s.mu.Unlock()
+	defer s.inflight.Done()
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.conns[conn.GetId()] = begin.FromContext(ctx)
+	s.mu.Unlock()
+	return conn, nil
+}
+
+// Close 关闭连接并停止跟踪
+func (s *Server) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.mu.Lock()
+	delete(s.conns, conn.GetId())
+	s.mu.Unlock()
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+// Shutdown 停止接受新的 Request，等待处理中的 Request 完成后关闭全部活动连接
+//
+// 功能说明:
+//   - 开始关闭后全部 Request（包括已有连接的刷新）返回 Unavailable
+//   - 活动连接通过连接的事件工厂并发关闭，与连接超时关闭相同经过完整链，链中各元素释放资源（如删除 ACL）
+//
+// 参数:
+//   - ctx: 上下文，到期时停止等待
+//
+// 返回:
+//   - int: 成功关闭的连接数
+//   - error: 部分连接关闭失败，或 ctx 到期时仍有请求或连接未处理完
+func (s *Server) Shutdown(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	s.draining = true
+	s.mu.Unlock()
+
+	idle := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(idle)
+	}()
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		return 0, errors.Wrap(ctx.Err(), "等待处理中的请求超时")
+	}
+
+	s.mu.Lock()
+	results := make(map[string]<-chan error, len(s.conns))
+	for connID, eventFactory := range s.conns {
+		results[connID] = eventFactory.Close()
+	}
+	s.mu.Unlock()
+
+	logger := log.FromContext(ctx).WithField("drain", "Shutdown")
+	closed, failed := 0, 0
+	for connID, result := range results {
+		select {
+		case err := <-result:
+			if err != nil {
+				failed++
+				logger.Errorf("关闭连接 %s 失败: %v", connID, err)
+				continue
+			}
+			closed++
+		case <-ctx.Done():
+			return closed, errors.Wrapf(ctx.Err(), "关闭连接超时，%d 个连接未关闭", len(results)-closed-failed)
+		}
+	}
+	if failed > 0 {
+		return closed, errors.Errorf("%d 个连接关闭失败", failed)
+	}
+	return closed, nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package health 提供防火墙 NSE 的健康检查：标准 gRPC 健康检查服务以及 HTTP /healthz、/readyz 探针
+package health
+
+import (
+	"sync"
+)
+
+// Check 一项健康检查，返回 nil 表示通过
+type Check func() error
+
+// namedCheck 带名称的健康检查
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Result 一组健康检查的结果
+//
+// 字段说明:
+//   - OK: 全部检查是否通过
+//   - Checks: 检查名称到结果的映射，通过为 "ok"，失败为失败原因
+type Result struct {
+	OK     bool              `json:"ok"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Checker 汇总存活检查（liveness）和就绪检查（readiness）
+//
+// 存活检查失败表示进程已无法恢复，应当重启；就绪检查失败表示暂时不能提供服务（如启动尚未完成）。
+// 就绪检查同时包含全部存活检查。检查可以在运行中随时添加，如各启动阶段完成后添加对应的检查。
+type Checker struct {
+	mu        sync.Mutex
+	liveness  []namedCheck
+	readiness []namedCheck
+}
+
+// NewChecker 创建没有任何检查的健康检查器
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// AddLivenessCheck 添加存活检查
+func (c *Checker) AddLivenessCheck(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.liveness = append(c.liveness, namedCheck{name: name, check: check})
+}
+
+// AddReadinessCheck 添加就绪检查
+func (c *Checker) AddReadinessCheck(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readiness = append(c.readiness, namedCheck{name: name, check: check})
+}
+
+// Live 执行全部存活检查
+func (c *Checker) Live() *Result {
+	c.mu.Lock()
+	checks := append([]namedCheck{}, c.liveness...)
+	c.mu.Unlock()
+	return run(checks)
+}
+
+// Ready 执行全部存活检查和就绪检查
+func (c *Checker) Ready() *Result {
+	c.mu.Lock()
+	checks := append(append([]namedCheck{}, c.liveness...), c.readiness...)
+	c.mu.Unlock()
+	return run(checks)
+}
+
+// run 依次执行检查并汇总结果
+func run(checks []namedCheck) *Result {
+	rv := &Result{OK: true, Checks: make(map[string]string, len(checks))}
+	for _, c := range checks {
+		if err := c.check(); err != nil {
+			rv.OK = false
+			rv.Checks[c.name] = err.Error()
+			continue
+		}
+		rv.Checks[c.name] = "ok"
+	}
+	return rv
+}
+
+// Condition 由事件设置结果的检查，如当前启动阶段、VPP 是否已连接
+type Condition struct {
+	mu  sync.Mutex
+	err error
+}
+
+// NewCondition 创建初始结果为 err 的检查条件，err 为 nil 表示通过
+func NewCondition(err error) *Condition {
+	return &Condition{err: err}
+}
+
+// Set 设置检查结果，nil 表示通过
+func (c *Condition) Set(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+// Check 返回当前检查结果，可作为 Check 添加到 Checker
+func (c *Condition) Check() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// gRPC 健康检查服务的服务名：空服务名和 ReadinessService 对应就绪检查，LivenessService 对应存活检查
+const (
+	LivenessService  = "liveness"
+	ReadinessService = "readiness"
+)
+
+// updateInterval gRPC 健康检查服务状态的更新周期
+const updateInterval = time.Second
+
+// Handler 返回健康检查 HTTP 处理器
+//
+// GET /healthz 执行存活检查，GET /readyz 执行就绪检查；全部通过时返回 200，否则返回 503，
+// 响应为 JSON 格式的各项检查结果（见 Result）。
+func (c *Checker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeResult(w, c.Live())
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		writeResult(w, c.Ready())
+	})
+	return mux
+}
+
+// RegisterGRPC 在 server 上注册标准 gRPC 健康检查服务（grpc.health.v1.Health）
+//
+// 功能说明:
+//   - 每秒执行一次检查并更新服务状态：空服务名和 ReadinessService 为就绪检查结果，LivenessService 为存活检查结果
+//   - 检查结果变化时记录日志
+//   - ctx 结束时全部服务状态置为 NOT_SERVING
+func (c *Checker) RegisterGRPC(ctx context.Context, server grpc.ServiceRegistrar) {
+	healthServer := grpchealth.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	c.update(ctx, healthServer)
+
+	go func() {
+		ticker := time.NewTicker(updateInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				healthServer.Shutdown()
+				return
+			case <-ticker.C:
+				c.update(ctx, healthServer)
+			}
+		}
+	}()
+}
+
+// update 执行检查并更新 gRPC 健康检查服务状态，状态变化时记录日志
+func (c *Checker) update(ctx context.Context, healthServer *grpchealth.Server) {
+	logger := log.FromContext(ctx).WithField("health", "update")
+	live, ready := c.Live(), c.Ready()
+	for service, result := range map[string]*Result{LivenessService: live, ReadinessService: ready} {
+		status := servingStatus(result)
+		if previous, err := healthServer.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: service}); err == nil && previous.GetStatus() == status {
+			continue
+		}
+		if result.OK {
+			logger.Infof("健康检查 %s 通过", service)
+		} else {
+			logger.Warnf("健康检查 %s 失败: %s", service, failures(result))
+		}
+		healthServer.SetServingStatus(service, status)
+	}
+	healthServer.SetServingStatus("", servingStatus(ready))
+}
+
+// ListenAndServe 在 listenOn 上启动健康检查 HTTP 服务，ctx 结束时关闭服务
+func ListenAndServe(ctx context.Context, listenOn string, handler http.Handler) {
+	logger := log.FromContext(ctx).WithField("health", "ListenAndServe")
+	server := &http.Server{
+		Addr:         listenOn,
+		Handler:      handler,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	logger.Infof("健康检查端点已启用，监听地址: %s", listenOn)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Errorf("健康检查端点启动失败: %s", err.Error())
+	}
+}
+
+// ServeGRPC 在 listenOn 上以明文 TCP 启动独立的 gRPC 服务器，只提供健康检查服务（见 RegisterGRPC），
+// ctx 结束时停止服务
+//
+// 健康检查服务不挂载在端点的 mTLS gRPC 服务器上：kubelet 的 gRPC 探针不支持 TLS，
+// 且端点的 gRPC 服务器在启动后期才开始监听。
+func (c *Checker) ServeGRPC(ctx context.Context, listenOn string) {
+	logger := log.FromContext(ctx).WithField("health", "ServeGRPC")
+	listener, err := net.Listen("tcp", listenOn)
+	if err != nil {
+		logger.Errorf("gRPC 健康检查服务启动失败: %s", err.Error())
+		return
+	}
+
+	server := grpc.NewServer()
+	c.RegisterGRPC(ctx, server)
+	go func() {
+		<-ctx.Done()
+		server.Stop()
+	}()
+
+	logger.Infof("gRPC 健康检查服务已启用，监听地址: %s", listenOn)
+	if err := server.Serve(listener); err != nil {
+		logger.Errorf("gRPC 健康检查服务异常退出: %s", err.Error())
+	}
+}
+
+// servingStatus 返回检查结果对应的 gRPC 健康检查服务状态
+func servingStatus(result *Result) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if result.OK {
+		return grpc_health_v1.HealthCheckResponse_SERVING
+	}
+	return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+}
+
+// failures 返回检查结果中失败的检查及其原因
+func failures(result *Result) map[string]string {
+	rv := make(map[string]string)
+	for name, msg := range result.Checks {
+		if msg != "ok" {
+			rv[name] = msg
+		}
+	}
+	return rv
+}
+
+// writeResult 以 JSON 格式写出检查结果，检查未全部通过时返回 503
+func writeResult(w http.ResponseWriter, result *Result) {
+	w.Header().Set("Content-Type", "application/json")
+	if !result.OK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package macip
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/networkservicemesh/govpp/binapi/acl"
+	"github.com/networkservicemesh/govpp/binapi/acl_types"
+	"github.com/networkservicemesh/govpp/binapi/ethernet_types"
+	"github.com/networkservicemesh/govpp/binapi/ip_types"
+	"github.com/pkg/errors"
+	"go.fd.io/govpp/api"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk-vpp/pkg/tools/ifindex"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const (
+	// macipTag MACIP ACL 标签前缀
+	macipTag = "nsm-macip"
+)
+
+// exactMACMask 精确匹配 MAC 地址的掩码
+var exactMACMask = ethernet_types.MacAddress{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+// create 为连接创建 MACIP ACL 并应用到接口
+//
+// 功能说明:
+//  1. 由连接的源地址和源 MAC 生成允许规则（见 rules）
+//  2. 调用 MacipACLAdd 创建 MACIP ACL
+//  3. 调用 MacipACLInterfaceAddDel 应用到接口，失败时删除已创建的 ACL
+//
+// 参数:
+//   - ctx: 上下文
+//   - vppConn: VPP API 连接
+//   - conn: 连接
+//   - isClient: 是否为客户端模式
+//
+// 返回:
+//   - binding: 创建的 MACIP ACL 及其所在接口
+//   - bool: 是否创建了 MACIP ACL（连接没有源地址时不创建）
+//   - error: 错误信息
+func create(ctx context.Context, vppConn api.Connection, conn *networkservice.Connection, isClient bool) (binding, bool, error) {
+	logger := log.FromContext(ctx).WithField("macip_server", "create")
+
+	rules, err := rules(conn)
+	if err != nil {
+		return binding{}, false, err
+	}
+	if len(rules) == 0 {
+		logger.Debug("连接没有源地址，跳过 MACIP ACL")
+		return binding{}, false, nil
+	}
+
+	swIfIndex, ok := ifindex.Load(ctx, isClient)
+	if !ok {
+		return binding{}, false, errors.New("未找到软件接口索引 (swIfIndex)")
+	}
+
+	now := time.Now()
+	rsp, err := acl.NewServiceClient(vppConn).MacipACLAdd(ctx, &acl.MacipACLAdd{
+		Tag:   fmt.Sprintf("%s-%s", macipTag, conn.GetId()),
+		Count: uint32(len(rules)),
+		R:     rules,
+	})
+	if err != nil {
+		return binding{}, false, errors.Wrap(err, "VPP API MacipACLAdd 调用失败")
+	}
+	logger.WithField("aclIndex", rsp.ACLIndex).
+		WithField("duration", time.Since(now)).
+		WithField("vppapi", "MacipACLAdd").Debug("MACIP ACL 创建完成")
+
+	b := binding{aclIndex: rsp.ACLIndex, swIfIndex: swIfIndex}
+	if _, err := acl.NewServiceClient(vppConn).MacipACLInterfaceAddDel(ctx, &acl.MacipACLInterfaceAddDel{
+		IsAdd:     true,
+		SwIfIndex: swIfIndex,
+		ACLIndex:  rsp.ACLIndex,
+	}); err != nil {
+		if _, delErr := acl.NewServiceClient(vppConn).MacipACLDel(ctx, &acl.MacipACLDel{ACLIndex: rsp.ACLIndex}); delErr != nil {
+			logger.WithField("aclIndex", rsp.ACLIndex).Debugf("删除 MACIP ACL 失败: %v", delErr)
+		}
+		return binding{}, false, errors.Wrap(err, "VPP API MacipACLInterfaceAddDel 调用失败")
+	}
+	return b, true, nil
+}
+
+// del 从接口解绑并删除 MACIP ACL，失败只记录调试日志
+func del(ctx context.Context, vppConn api.Connection, b binding) {
+	logger := log.FromContext(ctx).WithField("macip_server", "delete").WithField("aclIndex", b.aclIndex)
+
+	if _, err := acl.NewServiceClient(vppConn).MacipACLInterfaceAddDel(ctx, &acl.MacipACLInterfaceAddDel{
+		IsAdd:     false,
+		SwIfIndex: b.swIfIndex,
+		ACLIndex:  b.aclIndex,
+	}); err != nil {
+		logger.Debugf("解绑 MACIP ACL 失败: %v", err)
+	}
+	if _, err := acl.NewServiceClient(vppConn).MacipACLDel(ctx, &acl.MacipACLDel{ACLIndex: b.aclIndex}); err != nil {
+		logger.Debugf("删除 MACIP ACL 失败: %v", err)
+	}
+}
+
+// rules 由连接上下文生成 MACIP 允许规则
+//
+// 功能说明:
+//   - 每个 IpContext.SrcIpAddrs 地址生成一条允许规则
+//   - EthernetContext.SrcMac（NSC 侧的 MAC）存在时要求源 MAC 精确匹配；
+//     IP 载荷的连接（如 IP 模式的 memif）没有二层地址，MAC 掩码为全零，只校验源地址
+//
+// 参数:
+//   - conn: 连接
+//
+// 返回:
+//   - []acl_types.MacipACLRule: MACIP 规则列表
+//   - error: 地址或 MAC 格式错误
+func rules(conn *networkservice.Connection) ([]acl_types.MacipACLRule, error) {
+	var mac, mask ethernet_types.MacAddress
+	if srcMac := conn.GetContext().GetEthernetContext().GetSrcMac(); srcMac != "" {
+		var err error
+		if mac, err = ethernet_types.ParseMacAddress(srcMac); err != nil {
+			return nil, errors.Wrapf(err, "无效的源 MAC 地址 %q", srcMac)
+		}
+		mask = exactMACMask
+	}
+
+	var rv []acl_types.MacipACLRule
+	for _, addr := range conn.GetContext().GetIpContext().GetSrcIpAddrs() {
+		prefix, err := ip_types.ParsePrefix(addr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "无效的源地址 %q", addr)
+		}
+		rv = append(rv, acl_types.MacipACLRule{
+			IsPermit:   acl_types.ACL_ACTION_API_PERMIT,
+			SrcMac:     mac,
+			SrcMacMask: mask,
+			SrcPrefix:  prefix,
+		})
+	}
+	return rv, nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package macip 提供 MACIP ACL（源 MAC 与源 IP 绑定）防伪造链式元素
+package macip
+
+import (
+	"context"
+
+	"github.com/edwarnicke/genericsync"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/govpp/binapi/interface_types"
+	"github.com/pkg/errors"
+	"go.fd.io/govpp/api"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/postpone"
+)
+
+// binding 一个连接的 MACIP ACL 及其所在接口
+type binding struct {
+	aclIndex  uint32
+	swIfIndex interface_types.InterfaceIndex
+}
+
+// macipServer MACIP 服务器结构体
+// 负责为每个连接创建、应用和删除 MACIP ACL
+//
+// 字段说明:
+//   - vppConn: VPP API 连接
+//   - bindings: 连接 ID 到 MACIP ACL 的映射（线程安全）
+type macipServer struct {
+	vppConn  api.Connection                   // VPP API 连接
+	bindings genericsync.Map[string, binding] // 连接 ID -> MACIP ACL（线程安全）
+}
+
+// NewServer 创建 MACIP NetworkServiceServer 链式元素
+//
+// 功能说明:
+//   - 建立连接时，按连接 IpContext 中的源地址和 EthernetContext 中的源 MAC
+//     生成允许列表，以 MACIP ACL 应用到 NSC 侧接口
+//   - 接口上只放行来自已分配地址（和 MAC）的流量，阻止 NSC 伪造源地址
+//   - 关闭连接时解绑并删除 MACIP ACL
+//
+// 参数:
+//   - vppConn: VPP API 连接
+//
+// 返回:
+//   - networkservice.NetworkServiceServer: NSM 网络服务服务器接口实现
+func NewServer(vppConn api.Connection) networkservice.NetworkServiceServer {
+	return &macipServer{vppConn: vppConn}
+}
+
+// Request 处理网络服务请求
+//
+// 处理流程:
+//
+//	Request → next.Server().Request() → 检查 MACIP ACL → 创建/跳过 → 返回连接
+func (m *macipServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	postponeCtxFunc := postpone.ContextWithValues(ctx)
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, loaded := m.bindings.Load(conn.GetId()); loaded {
+		return conn, nil
+	}
+
+	b, ok, err := create(ctx, m.vppConn, conn, metadata.IsClient(m))
+	if err != nil {
+		closeCtx, cancelClose := postponeCtxFunc()
+		defer cancelClose()
+
+		if _, closeErr := m.Close(closeCtx, conn); closeErr != nil {
+			err = errors.Wrapf(err, "连接关闭时发生错误: %s", closeErr.Error())
+		}
+		return nil, err
+	}
+	if ok {
+		m.bindings.Store(conn.GetId(), b)
+	}
+	return conn, nil
+}
+
+// Close 关闭连接并删除 MACIP ACL
+//
+// 处理流程:
+//
+//	Close → 加载 MACIP ACL → 解绑接口 → 删除 MACIP ACL → next.Server().Close()
+func (m *macipServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if b, ok := m.bindings.LoadAndDelete(conn.GetId()); ok {
+		del(ctx, m.vppConn, b)
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package macip_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/payload"
+	"github.com/networkservicemesh/govpp/binapi/acl"
+	"github.com/networkservicemesh/govpp/binapi/ethernet_types"
+	"github.com/networkservicemesh/govpp/binapi/interface_types"
+	"github.com/networkservicemesh/sdk-vpp/pkg/tools/ifindex"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/pkg/errors"
+	"go.fd.io/govpp/api"
+
+	"github.com/ifzzh/cmd-nse-template/internal/macip"
+)
+
+// testSwIfIndex 测试连接的 NSC 侧接口索引
+const testSwIfIndex interface_types.InterfaceIndex = 7
+
+// ifIndexServer 将 testSwIfIndex 作为连接的接口索引存入元数据，代替 memif 等机制元素
+type ifIndexServer struct{}
+
+func (ifIndexServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	ifindex.Store(ctx, metadata.IsClient(ifIndexServer{}), testSwIfIndex)
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (ifIndexServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+// fakeVPP 只实现 MACIP ACL 请求-响应调用的 api.Connection，记录创建、绑定和删除的 MACIP ACL
+type fakeVPP struct {
+	mu      sync.Mutex
+	adds    []*acl.MacipACLAdd
+	bound   map[uint32]interface_types.InterfaceIndex
+	deleted []uint32
+}
+
+func newFakeVPP() *fakeVPP {
+	return &fakeVPP{bound: make(map[uint32]interface_types.InterfaceIndex)}
+}
+
+func (f *fakeVPP) Invoke(_ context.Context, req, reply api.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r := req.(type) {
+	case *acl.MacipACLAdd:
+		reply.(*acl.MacipACLAddReply).ACLIndex = uint32(len(f.adds))
+		f.adds = append(f.adds, r)
+	case *acl.MacipACLInterfaceAddDel:
+		if r.IsAdd {
+			f.bound[r.ACLIndex] = r.SwIfIndex
+		} else {
+			delete(f.bound, r.ACLIndex)
+		}
+	case *acl.MacipACLDel:
+		f.deleted = append(f.deleted, r.ACLIndex)
+	default:
+		return errors.Errorf("未预期的 VPP API 调用 %s", req.GetMessageName())
+	}
+	return nil
+}
+
+func (f *fakeVPP) NewStream(context.Context, ...api.StreamOption) (api.Stream, error) {
+	return nil, errors.New("未实现")
+}
+
+func (f *fakeVPP) WatchEvent(context.Context, api.Message) (api.Watcher, error) {
+	return nil, errors.New("未实现")
+}
+
+// newTestServer 创建使用 fakeVPP 的 MACIP 服务器链
+func newTestServer(vpp *fakeVPP) networkservice.NetworkServiceServer {
+	return chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ifIndexServer{},
+		macip.NewServer(vpp),
+	)
+}
+
+func TestIPPayloadConnection(t *testing.T) {
+	vpp := newFakeVPP()
+	server := newTestServer(vpp)
+
+	// IP 模式的 memif 没有二层地址，连接上下文中没有 EthernetContext
+	conn, err := server.Request(context.Background(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:      "conn-1",
+			Payload: payload.IP,
+			Context: &networkservice.ConnectionContext{
+				IpContext: &networkservice.IPContext{SrcIpAddrs: []string{"10.0.0.2/32"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Request 失败: %v", err)
+	}
+
+	if len(vpp.adds) != 1 || len(vpp.adds[0].R) != 1 {
+		t.Fatalf("MACIP ACL = %+v，期望一个包含一条规则的 ACL", vpp.adds)
+	}
+	rule := vpp.adds[0].R[0]
+	if rule.SrcMacMask != (ethernet_types.MacAddress{}) {
+		t.Errorf("MAC 掩码 = %v，期望全零（只校验源地址）", rule.SrcMacMask)
+	}
+	if rule.SrcPrefix.String() != "10.0.0.2/32" {
+		t.Errorf("源前缀 = %v，期望 10.0.0.2/32", rule.SrcPrefix)
+	}
+	if swIfIndex, ok := vpp.bound[0]; !ok || swIfIndex != testSwIfIndex {
+		t.Fatalf("MACIP ACL 绑定 = %v，期望绑定在接口 %d 上", vpp.bound, testSwIfIndex)
+	}
+
+	if _, err = server.Close(context.Background(), conn); err != nil {
+		t.Fatalf("Close 失败: %v", err)
+	}
+	if len(vpp.bound) != 0 || len(vpp.deleted) != 1 {
+		t.Fatalf("关闭后绑定 = %v、删除 = %v，期望解绑并删除 MACIP ACL", vpp.bound, vpp.deleted)
+	}
+}
+
+func TestEthernetConnectionMatchesNSCMac(t *testing.T) {
+	vpp := newFakeVPP()
+	server := newTestServer(vpp)
+
+	_, err := server.Request(context.Background(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:      "conn-1",
+			Payload: payload.Ethernet,
+			Context: &networkservice.ConnectionContext{
+				IpContext:       &networkservice.IPContext{SrcIpAddrs: []string{"10.0.0.2/32"}},
+				EthernetContext: &networkservice.EthernetContext{SrcMac: "02:fe:00:00:00:01", DstMac: "02:fe:00:00:00:02"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Request 失败: %v", err)
+	}
+
+	if len(vpp.adds) != 1 || len(vpp.adds[0].R) != 1 {
+		t.Fatalf("MACIP ACL = %+v，期望一个包含一条规则的 ACL", vpp.adds)
+	}
+	rule := vpp.adds[0].R[0]
+	if rule.SrcMac.String() != "02:fe:00:00:00:01" {
+		t.Errorf("源 MAC = %v，期望 NSC 侧的 MAC 02:fe:00:00:00:01", rule.SrcMac)
+	}
+	if rule.SrcMacMask.String() != "ff:ff:ff:ff:ff:ff" {
+		t.Errorf("MAC 掩码 = %v，期望精确匹配", rule.SrcMacMask)
+	}
+}
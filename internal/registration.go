@@ -0,0 +1,390 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package internal
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	registryapi "github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// defaultRegistrationTTL 默认注册有效期
+	defaultRegistrationTTL = time.Minute
+	// registrationMinBackoff 注册失败后首次重试前的等待时间，之后每次翻倍
+	registrationMinBackoff = time.Second
+	// registrationMinRefresh 两次刷新之间的最短间隔
+	registrationMinRefresh = time.Second
+)
+
+// RegistrationStatus 端点注册状态
+//
+// 字段说明:
+//   - Registered: 端点已注册且注册尚未到期（最近一次刷新失败时，注册在到期前仍然有效）
+//   - ExpiresAt: 注册到期时间
+//   - LastRefresh: 最近一次注册成功的时间
+//   - ConsecutiveFailures: 连续注册失败次数
+//   - LastError: 最近一次注册失败的原因，注册成功后清空
+type RegistrationStatus struct {
+	Name                string    `json:"name"`
+	Registered          bool      `json:"registered"`
+	ExpiresAt           time.Time `json:"expiresAt,omitempty"`
+	LastRefresh         time.Time `json:"lastRefresh,omitempty"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	LastError           string    `json:"lastError,omitempty"`
+}
+
+// Registration 端点注册监督
+//
+// 功能说明:
+//   - 注册时设置到期时间（ExpirationTime），在剩余有效期的三分之一处刷新注册
+//   - 注册失败（如 NSM Manager 重启、注册已过期）时按指数退避重新注册
+//   - 一个端点可以提供多个网络服务，每个网络服务的标签为公共标签加上该网络服务的标签（见 ServiceConfig）
+//   - 公共标签变化时重新注册端点以更新 NSM 中的端点标签
+//   - 注册状态通过 Status、就绪条件 Ready 和 OpenTelemetry 指标 nse_registered、nse_registration_failures 导出
+//
+// 每次注册调用在注册有效期的三分之一内返回；callMu 串行化注册中心调用，调用期间不持有 mu。
+// 注册状态变化时发布到 published，Status 和 Ready 只读取已发布的状态而不加锁，
+// 注册中心不可用或注册调用进行中时健康检查和管理端点不会被阻塞。
+type Registration struct {
+	callMu        sync.Mutex
+	mu            sync.Mutex
+	labels        map[string]string
+	serviceLabels map[string]map[string]string
+	ttl           time.Duration
+	maxBackoff    time.Duration
+	client        registryapi.NetworkServiceEndpointRegistryClient
+	nse           *registryapi.NetworkServiceEndpoint
+	stop          context.CancelFunc
+	status        RegistrationStatus
+	published     atomic.Pointer[RegistrationStatus]
+	metricsOnce   sync.Once
+	failures      metric.Int64Counter
+}
+
+// RegistrationOption 端点注册的可选配置项
+type RegistrationOption func(r *Registration)
+
+// WithExpiration 设置注册有效期，默认 1 分钟
+func WithExpiration(ttl time.Duration) RegistrationOption {
+	return func(r *Registration) {
+		if ttl > 0 {
+			r.ttl = ttl
+		}
+	}
+}
+
+// WithMaxBackoff 设置注册失败后重试的最长等待时间，默认与注册有效期的三分之一相同
+func WithMaxBackoff(backoff time.Duration) RegistrationOption {
+	return func(r *Registration) {
+		r.maxBackoff = backoff
+	}
+}
+
+// NewRegistration 创建以 labels 为初始公共标签的端点注册
+func NewRegistration(labels map[string]string, options ...RegistrationOption) *Registration {
+	r := &Registration{
+		labels: make(map[string]string, len(labels)),
+		ttl:    defaultRegistrationTTL,
+	}
+	for k, v := range labels {
+		r.labels[k] = v
+	}
+	for _, opt := range options {
+		opt(r)
+	}
+	if r.maxBackoff <= 0 {
+		r.maxBackoff = r.ttl / 3
+	}
+	return r
+}
+
+// Register 使用当前标签注册网络服务端点，并启动注册监督直到 ctx 结束或 Unregister
+//
+// 参数:
+//   - ctx: 上下文，结束时停止刷新和重新注册
+//   - client: 注册中心客户端（见 NewRegistryClient）
+//   - name: 端点名称
+//   - services: 端点提供的网络服务
+//   - listenURL: 端点监听地址
+//
+// 返回:
+//   - *registryapi.NetworkServiceEndpoint: 注册成功的端点
+//   - error: 首次注册失败，此时注册监督仍会在后台按退避重试
+func (r *Registration) Register(ctx context.Context, client registryapi.NetworkServiceEndpointRegistryClient, name string, services []ServiceConfig, listenURL string) (*registryapi.NetworkServiceEndpoint, error) {
+	r.metricsOnce.Do(func() { r.initMetrics(ctx) })
+
+	r.callMu.Lock()
+	defer r.callMu.Unlock()
+
+	r.mu.Lock()
+	r.client = client
+	r.nse = &registryapi.NetworkServiceEndpoint{
+		Name:                name,
+		NetworkServiceNames: ServiceNames(services),
+		Url:                 listenURL,
+	}
+	r.serviceLabels = make(map[string]map[string]string, len(services))
+	for i := range services {
+		r.serviceLabels[services[i].Name] = services[i].Labels
+	}
+	r.status = RegistrationStatus{Name: name}
+	r.publishStatus()
+	superviseCtx, stop := context.WithCancel(ctx)
+	r.stop = stop
+	r.mu.Unlock()
+
+	nse, err := r.register(ctx)
+	go r.supervise(superviseCtx)
+	return nse, err
+}
+
+// Unregister 停止注册监督并从注册中心注销端点，之后标签变化不再重新注册；端点未注册时不做任何操作
+func (r *Registration) Unregister(ctx context.Context) error {
+	r.mu.Lock()
+	if r.client == nil {
+		r.mu.Unlock()
+		return nil
+	}
+	r.stop()
+	r.mu.Unlock()
+
+	// 等待进行中的注册调用返回，之后不再有新的注册调用
+	r.callMu.Lock()
+	defer r.callMu.Unlock()
+	r.mu.Lock()
+	registered := !r.status.LastRefresh.IsZero()
+	client, nse := r.client, r.nse
+	r.client, r.nse = nil, nil
+	r.status.ExpiresAt = time.Time{}
+	r.publishStatus()
+	r.mu.Unlock()
+	if !registered {
+		return nil
+	}
+	if _, err := client.Unregister(ctx, nse); err != nil {
+		return errors.Wrapf(err, "注销端点 %s 失败", nse.GetName())
+	}
+	return nil
+}
+
+// SetLabel 设置全部网络服务的公共端点标签，value 为空时删除该标签；端点已注册时重新注册
+func (r *Registration) SetLabel(ctx context.Context, key, value string) error {
+	return r.UpdateLabels(ctx, map[string]string{key: value})
+}
+
+// UpdateLabels 批量设置公共端点标签，值为空的标签被删除，其他标签不变；端点已注册时重新注册一次
+//
+// 注册调用在注册有效期的三分之一内返回；失败时新标签已保存，由注册监督在下一次注册时带上。
+func (r *Registration) UpdateLabels(ctx context.Context, labels map[string]string) error {
+	r.mu.Lock()
+	for key, value := range labels {
+		if value == "" {
+			delete(r.labels, key)
+		} else {
+			r.labels[key] = value
+		}
+	}
+	r.mu.Unlock()
+
+	r.callMu.Lock()
+	defer r.callMu.Unlock()
+	_, err := r.register(ctx)
+	return err
+}
+
+// Status 返回最近一次发布的端点注册状态，不等待进行中的注册调用
+func (r *Registration) Status() RegistrationStatus {
+	var status RegistrationStatus
+	if published := r.published.Load(); published != nil {
+		status = *published
+	}
+	status.Registered = time.Now().Before(status.ExpiresAt)
+	return status
+}
+
+// publishStatus 发布当前注册状态供 Status 和 Ready 读取，调用方需持有 r.mu
+func (r *Registration) publishStatus() {
+	status := r.status
+	r.published.Store(&status)
+}
+
+// Ready 就绪条件：端点已注册且注册尚未到期时返回 nil，否则返回原因；不加锁，可在健康检查中直接调用
+func (r *Registration) Ready() error {
+	status := r.Status()
+	switch {
+	case status.Registered:
+		return nil
+	case status.LastError != "":
+		return errors.Errorf("端点未注册: %s", status.LastError)
+	default:
+		return errors.New("端点未注册")
+	}
+}
+
+// register 以当前标签和新的到期时间注册端点并更新注册状态，端点未注册或已注销时不做任何操作
+//
+// 调用方需持有 r.callMu（不能持有 r.mu）；注册调用的超时为注册有效期的三分之一，调用期间不持有 r.mu。
+func (r *Registration) register(ctx context.Context) (*registryapi.NetworkServiceEndpoint, error) {
+	r.mu.Lock()
+	if r.client == nil {
+		r.mu.Unlock()
+		return nil, nil
+	}
+	client := r.client
+	nse := r.nse.Clone()
+	nse.NetworkServiceLabels = make(map[string]*registryapi.NetworkServiceLabels, len(nse.GetNetworkServiceNames()))
+	for _, serviceName := range nse.GetNetworkServiceNames() {
+		nse.NetworkServiceLabels[serviceName] = &registryapi.NetworkServiceLabels{Labels: r.serviceLabelsOf(serviceName)}
+	}
+	nse.ExpirationTime = timestamppb.New(time.Now().Add(r.ttl))
+	r.mu.Unlock()
+
+	callCtx, cancel := context.WithTimeout(ctx, r.ttl/3)
+	defer cancel()
+	registered, err := client.Register(callCtx, nse)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	defer r.publishStatus()
+	if err != nil {
+		r.status.ConsecutiveFailures++
+		r.status.LastError = err.Error()
+		if r.failures != nil {
+			r.failures.Add(ctx, 1)
+		}
+		return nil, errors.Wrapf(err, "注册端点 %s 失败", nse.GetName())
+	}
+
+	r.nse = registered
+	r.status.LastRefresh = time.Now()
+	r.status.ExpiresAt = nse.GetExpirationTime().AsTime()
+	if registered.GetExpirationTime() != nil {
+		r.status.ExpiresAt = registered.GetExpirationTime().AsTime()
+	}
+	r.status.ConsecutiveFailures = 0
+	r.status.LastError = ""
+	return registered, nil
+}
+
+// supervise 在注册到期前刷新注册，注册失败后按退避重新注册，ctx 结束时停止
+func (r *Registration) supervise(ctx context.Context) {
+	logger := log.FromContext(ctx).WithField("registration", "supervise")
+	timer := time.NewTimer(r.nextAttempt())
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		r.callMu.Lock()
+		if ctx.Err() != nil {
+			r.callMu.Unlock()
+			return
+		}
+		recovering := r.Status().ConsecutiveFailures > 0
+		_, err := r.register(ctx)
+		r.callMu.Unlock()
+		status := r.Status()
+		failures, expiresAt := status.ConsecutiveFailures, status.ExpiresAt
+
+		switch {
+		case err != nil:
+			logger.Warnf("端点注册失败（连续 %d 次），注册到期时间 %v: %v", failures, expiresAt, err)
+		case recovering:
+			logger.Infof("端点重新注册成功，注册到期时间 %v", expiresAt)
+		default:
+			logger.Debugf("端点注册已刷新，注册到期时间 %v", expiresAt)
+		}
+		timer.Reset(r.nextAttempt())
+	}
+}
+
+// nextAttempt 返回距下一次注册的等待时间：注册失败后按指数退避，否则在剩余有效期的三分之一处刷新
+func (r *Registration) nextAttempt() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if failures := r.status.ConsecutiveFailures; failures > 0 {
+		backoff := registrationMinBackoff
+		for i := 1; i < failures && backoff < r.maxBackoff; i++ {
+			backoff *= 2
+		}
+		if backoff > r.maxBackoff {
+			backoff = r.maxBackoff
+		}
+		return backoff
+	}
+	if refresh := time.Until(r.status.ExpiresAt) / 3; refresh > registrationMinRefresh {
+		return refresh
+	}
+	return registrationMinRefresh
+}
+
+// initMetrics 创建注册状态指标，OpenTelemetry 未启用时不记录
+func (r *Registration) initMetrics(ctx context.Context) {
+	if !opentelemetry.IsEnabled() {
+		return
+	}
+	logger := log.FromContext(ctx).WithField("registration", "metrics")
+	meter := otel.Meter("")
+	failures, err := meter.Int64Counter("nse_registration_failures",
+		metric.WithDescription("Number of failed endpoint registrations and refreshes"))
+	if err != nil {
+		logger.Errorf("创建指标失败: %v", err)
+		return
+	}
+	r.failures = failures
+	_, err = meter.Int64ObservableGauge("nse_registered",
+		metric.WithDescription("Whether the endpoint registration is valid (1) or not (0)"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			var registered int64
+			if r.Status().Registered {
+				registered = 1
+			}
+			o.Observe(registered)
+			return nil
+		}))
+	if err != nil {
+		logger.Errorf("创建指标失败: %v", err)
+	}
+}
+
+// serviceLabelsOf 返回网络服务的端点标签：公共标签加上该网络服务的标签（同名时以后者为准），调用方需持有 r.mu
+func (r *Registration) serviceLabelsOf(serviceName string) map[string]string {
+	labels := make(map[string]string, len(r.labels)+len(r.serviceLabels[serviceName]))
+	for k, v := range r.labels {
+		labels[k] = v
+	}
+	for k, v := range r.serviceLabels[serviceName] {
+		labels[k] = v
+	}
+	return labels
+}
@@ -26,41 +26,45 @@ package internal
 import (
 	"context"
 	"net/url"
+	"time"
 
 	registryapi "github.com/networkservicemesh/api/pkg/api/registry"
-	registryclient "github.com/networkservicemesh/sdk/pkg/registry/chains/client"
 	registryauthorize "github.com/networkservicemesh/sdk/pkg/registry/common/authorize"
+	registrybegin "github.com/networkservicemesh/sdk/pkg/registry/common/begin"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/clientconn"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/clientinfo"
+	registryclienturl "github.com/networkservicemesh/sdk/pkg/registry/common/clienturl"
+	registryconnect "github.com/networkservicemesh/sdk/pkg/registry/common/connect"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/dial"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/grpcmetadata"
 	registrysendfd "github.com/networkservicemesh/sdk/pkg/registry/common/sendfd"
+	registrychain "github.com/networkservicemesh/sdk/pkg/registry/core/chain"
+	registrymetadata "github.com/networkservicemesh/sdk/pkg/registry/utils/metadata"
 	"google.golang.org/grpc"
 )
 
+// registryDialTimeout 连接注册中心的拨号超时，与 SDK 注册中心客户端的默认值相同
+const registryDialTimeout = 300 * time.Millisecond
+
 // NewRegistryClient 创建NSM Registry客户端
 // 配置了客户端URL、拨号选项和授权策略
+//
+// 与 SDK 的注册中心客户端链（registry/chains/client）相同，但不包含 refresh、heal 和 retry：
+// 注册的到期、刷新和失败后的重新注册只由注册监督（见 Registration）负责，每次调用在调用方的超时内返回
 func NewRegistryClient(ctx context.Context, connectTo *url.URL, clientOptions []grpc.DialOption, registryPolicies []string) registryapi.NetworkServiceEndpointRegistryClient {
-	return registryclient.NewNetworkServiceEndpointRegistryClient(
-		ctx,
-		registryclient.WithClientURL(connectTo),
-		registryclient.WithDialOptions(clientOptions...),
-		registryclient.WithNSEAdditionalFunctionality(
-			clientinfo.NewNetworkServiceEndpointRegistryClient(),
-			registrysendfd.NewNetworkServiceEndpointRegistryClient()),
-		registryclient.WithAuthorizeNSERegistryClient(registryauthorize.NewNetworkServiceEndpointRegistryClient(
-			registryauthorize.WithPolicies(registryPolicies...))),
+	return registrychain.NewNetworkServiceEndpointRegistryClient(
+		registrybegin.NewNetworkServiceEndpointRegistryClient(),
+		registrymetadata.NewNetworkServiceEndpointClient(),
+		registryauthorize.NewNetworkServiceEndpointRegistryClient(registryauthorize.WithPolicies(registryPolicies...)),
+		registryclienturl.NewNetworkServiceEndpointRegistryClient(connectTo),
+		clientconn.NewNetworkServiceEndpointRegistryClient(),
+		grpcmetadata.NewNetworkServiceEndpointRegistryClient(),
+		dial.NewNetworkServiceEndpointRegistryClient(ctx,
+			dial.WithDialTimeout(registryDialTimeout),
+			dial.WithDialOptions(clientOptions...),
+		),
+		clientinfo.NewNetworkServiceEndpointRegistryClient(),
+		registrysendfd.NewNetworkServiceEndpointRegistryClient(),
+		registryconnect.NewNetworkServiceEndpointRegistryClient(),
 	)
 }
-
-// RegisterEndpoint 向NSM Manager注册网络服务端点
-// 构造NetworkServiceEndpoint对象并发起注册请求
-func RegisterEndpoint(ctx context.Context, client registryapi.NetworkServiceEndpointRegistryClient, name string, serviceName string, labels map[string]string, listenURL string) (*registryapi.NetworkServiceEndpoint, error) {
-	return client.Register(ctx, &registryapi.NetworkServiceEndpoint{
-		Name:                name,
-		NetworkServiceNames: []string{serviceName},
-		NetworkServiceLabels: map[string]*registryapi.NetworkServiceLabels{
-			serviceName: {
-				Labels: labels,
-			},
-		},
-		Url: listenURL,
-	})
-}
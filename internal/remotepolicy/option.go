@@ -0,0 +1,73 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package remotepolicy
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ifzzh/cmd-nse-template/internal/signature"
+)
+
+// Option 策略轮询器的可选配置项
+type Option func(p *Poller)
+
+// WithInterval 设置轮询间隔，默认 30 秒
+func WithInterval(interval time.Duration) Option {
+	return func(p *Poller) {
+		p.interval = interval
+	}
+}
+
+// WithCachePath 设置本地缓存文件路径
+//
+// 每次成功获取并校验策略后写入该文件；启动时策略服务器不可达，则从该文件加载
+// 最近一次有效的策略。未设置时不缓存。
+func WithCachePath(path string) Option {
+	return func(p *Poller) {
+		p.cachePath = path
+	}
+}
+
+// WithHTTPClient 替换访问策略服务器的 HTTP 客户端
+//
+// 可用于配置 HTTPS 根证书和超时；测试中可以传入 httptest.Server.Client()。
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Poller) {
+		p.client = client
+	}
+}
+
+// WithVerifier 要求策略带有分离签名，并用 verifier 校验
+//
+// 签名默认从策略地址加上 .sig 后缀的地址获取（见 WithSignatureURL），本地缓存的签名
+// 保存在缓存文件加上 .sig 后缀的文件中。签名校验失败的策略不生效，并记录审计事件。
+func WithVerifier(verifier *signature.Verifier) Option {
+	return func(p *Poller) {
+		p.verifier = verifier
+	}
+}
+
+// WithSignatureURL 设置分离签名的地址
+func WithSignatureURL(url string) Option {
+	return func(p *Poller) {
+		p.signatureURL = url
+	}
+}
@@ -0,0 +1,334 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package remotepolicy 从 HTTP(S) 策略服务器轮询防火墙规则
+package remotepolicy
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/pkg/errors"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+	"github.com/ifzzh/cmd-nse-template/internal/audit"
+	"github.com/ifzzh/cmd-nse-template/internal/signature"
+)
+
+const (
+	// defaultInterval 默认轮询间隔
+	defaultInterval = 30 * time.Second
+	// maxPolicySize 策略内容大小上限
+	maxPolicySize = 16 << 20
+)
+
+// 策略来源
+const (
+	SourceServer = "server"
+	SourceCache  = "cache"
+)
+
+// Status 策略轮询状态
+//
+// 字段说明:
+//   - URL: 策略服务器地址
+//   - Source: 当前生效策略的来源（server 或 cache），尚未加载任何策略时为空
+//   - ETag: 当前生效策略的 ETag
+//   - Rules: 当前生效策略的规则数
+//   - LastPoll: 最近一次轮询时间
+//   - LastSuccess: 最近一次成功轮询（含 304 未修改）的时间
+//   - Error: 最近一次轮询的错误，成功时为空
+type Status struct {
+	URL         string    `json:"url"`
+	Source      string    `json:"source"`
+	ETag        string    `json:"etag"`
+	Rules       int       `json:"rules"`
+	LastPoll    time.Time `json:"lastPoll"`
+	LastSuccess time.Time `json:"lastSuccess"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// Poller 定期从策略服务器获取规则并更新防火墙策略
+//
+// 请求携带 If-None-Match，服务器返回 304 时不重新解析和下发规则；
+// 获取的策略与本地配置文件使用相同的校验（见 acl.ParseRuleSets），配置了签名校验器时还须
+// 通过签名校验；校验失败时保留当前策略，并记录审计事件。
+// 策略变化时替换全部配置的规则集，包括通过管理接口在运行时修改的规则。
+type Poller struct {
+	url          string
+	signatureURL string
+	policy       *acl.Policy
+	client       *http.Client
+	interval     time.Duration
+	cachePath    string
+	verifier     *signature.Verifier
+
+	mu     sync.Mutex
+	body   []byte
+	status Status
+}
+
+// NewPoller 创建策略轮询器
+//
+// 参数:
+//   - policy: 防火墙策略，获取的规则集通过 SetRuleSets 替换配置的规则集
+//   - serverURL: 策略服务器地址
+//   - options: 可选配置项（如 WithInterval、WithCachePath）
+//
+// 返回:
+//   - *Poller: 策略轮询器
+func NewPoller(policy *acl.Policy, serverURL string, options ...Option) *Poller {
+	p := &Poller{
+		url:      serverURL,
+		policy:   policy,
+		client:   http.DefaultClient,
+		interval: defaultInterval,
+		status:   Status{URL: serverURL},
+	}
+	for _, opt := range options {
+		opt(p)
+	}
+	if p.signatureURL == "" {
+		p.signatureURL = serverURL + signature.Suffix
+		if u, err := url.Parse(serverURL); err == nil {
+			u.Path += signature.Suffix
+			p.signatureURL = u.String()
+		}
+	}
+	return p
+}
+
+// Start 获取一次策略并开始定期轮询，ctx 结束时停止
+//
+// 首次获取失败时从本地缓存加载最近一次有效的策略；缓存也不可用时保留当前策略
+// （即本地配置文件中的规则），之后的轮询成功后再替换。
+func (p *Poller) Start(ctx context.Context) {
+	logger := log.FromContext(ctx).WithField("remotepolicy", p.url)
+	if err := p.Poll(ctx); err != nil {
+		logger.Warnf("获取策略失败: %v", err)
+		if cacheErr := p.loadCache(ctx); cacheErr != nil {
+			logger.Warnf("加载本地缓存的策略失败，保留本地配置的规则: %v", cacheErr)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := p.Poll(ctx); err != nil {
+					logger.Warnf("获取策略失败，保留当前策略: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Poll 向策略服务器请求一次策略
+//
+// 功能说明:
+//   - 携带上次响应的 ETag 发送 If-None-Match，返回 304 时策略不变
+//   - 返回 200 时校验策略，内容有变化才更新防火墙策略，并写入本地缓存
+//   - 其他状态码、网络错误和校验失败都返回错误，当前策略保持不变
+//
+// 返回:
+//   - error: 错误信息
+func (p *Poller) Poll(ctx context.Context) error {
+	err := p.poll(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status.LastPoll = time.Now()
+	p.status.Error = ""
+	if err != nil {
+		p.status.Error = err.Error()
+		return err
+	}
+	p.status.LastSuccess = p.status.LastPoll
+	return nil
+}
+
+// Status 返回策略轮询状态
+func (p *Poller) Status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status
+}
+
+// poll 请求并应用一次策略
+func (p *Poller) poll(ctx context.Context) error {
+	p.mu.Lock()
+	etag := p.status.ETag
+	p.mu.Unlock()
+
+	body, etag, err := p.fetch(ctx, p.url, etag)
+	if err != nil || body == nil {
+		return err
+	}
+	var sig []byte
+	if p.verifier != nil {
+		if sig, _, err = p.fetch(ctx, p.signatureURL, ""); err != nil {
+			return errors.Wrap(err, "获取策略签名失败")
+		}
+	}
+	if err = p.apply(ctx, p.url, body, sig, SourceServer, etag); err != nil {
+		return err
+	}
+	p.saveCache(ctx, body, sig)
+	return nil
+}
+
+// fetch 发送 GET 请求，etag 不为空时携带 If-None-Match
+//
+// 返回:
+//   - body: 响应内容，服务器返回 304 时为 nil
+//   - etag: 响应的 ETag
+//   - err: 网络错误、非 200/304 状态码或内容超过大小上限
+func (p *Poller) fetch(ctx context.Context, target, etag string) (body []byte, respETag string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, http.NoBody)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "创建策略请求失败")
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "请求策略服务器失败")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil, etag, nil
+	case http.StatusOK:
+	default:
+		return nil, "", errors.Errorf("策略服务器返回 %s", resp.Status)
+	}
+
+	body, err = io.ReadAll(io.LimitReader(resp.Body, maxPolicySize+1))
+	if err != nil {
+		return nil, "", errors.Wrap(err, "读取策略失败")
+	}
+	if len(body) > maxPolicySize {
+		return nil, "", errors.Errorf("策略超过 %d 字节", maxPolicySize)
+	}
+	return body, resp.Header.Get("ETag"), nil
+}
+
+// apply 校验策略并更新防火墙策略，内容与当前策略相同时只更新状态
+//
+// 签名或规则校验失败时记录审计事件，当前策略保持不变。
+func (p *Poller) apply(ctx context.Context, origin string, body, sig []byte, source, etag string) error {
+	if p.verifier != nil {
+		if err := p.verifier.Verify(body, sig); err != nil {
+			audit.Record(ctx, audit.PolicyRejected, origin, "策略签名校验失败: "+err.Error())
+			return errors.Wrap(err, "策略签名校验失败")
+		}
+	}
+	sets, err := acl.ParseRuleSets(body)
+	if err != nil {
+		audit.Record(ctx, audit.PolicyRejected, origin, "策略规则校验失败: "+err.Error())
+		return errors.Wrap(err, "策略校验失败")
+	}
+
+	p.mu.Lock()
+	changed := !bytes.Equal(p.body, body)
+	p.body = body
+	rules := len(acl.Flatten(sets))
+	p.status.Source, p.status.ETag, p.status.Rules = source, etag, rules
+	p.mu.Unlock()
+
+	if changed {
+		log.FromContext(ctx).WithField("remotepolicy", p.url).Infof("更新策略（来源 %s，ETag %s），规则 %d 条", source, etag, rules)
+		p.policy.SetRuleSets(ctx, sets)
+	}
+	return nil
+}
+
+// loadCache 从本地缓存加载策略，配置了签名校验器时同时加载缓存的签名
+func (p *Poller) loadCache(ctx context.Context) error {
+	if p.cachePath == "" {
+		return errors.New("未配置本地缓存")
+	}
+	body, err := os.ReadFile(filepath.Clean(p.cachePath))
+	if err != nil {
+		return errors.Wrap(err, "读取本地缓存失败")
+	}
+	var sig []byte
+	if p.verifier != nil {
+		if sig, err = os.ReadFile(filepath.Clean(p.cachePath + signature.Suffix)); err != nil {
+			return errors.Wrap(err, "读取本地缓存的签名失败")
+		}
+	}
+	// 缓存的策略不带 ETag，下次轮询时从服务器获取完整策略
+	return p.apply(ctx, p.cachePath, body, sig, SourceCache, "")
+}
+
+// saveCache 将策略和签名写入本地缓存
+func (p *Poller) saveCache(ctx context.Context, body, sig []byte) {
+	if p.cachePath == "" {
+		return
+	}
+	logger := log.FromContext(ctx).WithField("remotepolicy", "saveCache")
+	if err := os.MkdirAll(filepath.Dir(p.cachePath), 0o750); err != nil {
+		logger.Warnf("创建缓存目录失败: %v", err)
+		return
+	}
+	if sig != nil {
+		if err := writeFile(p.cachePath+signature.Suffix, sig); err != nil {
+			logger.Warnf("写入缓存的签名失败: %v", err)
+			return
+		}
+	}
+	if err := writeFile(p.cachePath, body); err != nil {
+		logger.Warnf("写入缓存文件失败: %v", err)
+	}
+}
+
+// writeFile 先写临时文件再重命名，避免留下不完整的文件
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
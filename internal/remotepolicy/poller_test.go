@@ -0,0 +1,286 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package remotepolicy_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+	"github.com/ifzzh/cmd-nse-template/internal/audit"
+	"github.com/ifzzh/cmd-nse-template/internal/remotepolicy"
+)
+
+const (
+	localPolicy = `
+allow local:
+  srcprefix: 10.0.0.0/8
+  ispermit: 1
+`
+	serverPolicy = `
+allow from server:
+  srcprefix: 192.168.0.0/16
+  ispermit: 1
+`
+	invalidPolicy = `
+allow from server:
+  srcprefix: not-a-prefix
+  ispermit: 1
+`
+)
+
+// policyServer 测试用策略服务器，返回 body 和 etag，请求携带相同的 If-None-Match 时返回 304
+type policyServer struct {
+	mu          sync.Mutex
+	status      int
+	body        string
+	etag        string
+	notModified int
+}
+
+func (s *policyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status != http.StatusOK {
+		w.WriteHeader(s.status)
+		return
+	}
+	if s.etag != "" && r.Header.Get("If-None-Match") == s.etag {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", s.etag)
+	_, _ = w.Write([]byte(s.body))
+}
+
+func (s *policyServer) set(status int, body, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.body, s.etag = status, body, etag
+}
+
+// newPolicy 创建以 localPolicy 为配置规则的策略，返回策略和其变化次数的计数函数
+func newPolicy(t *testing.T) (policy *acl.Policy, updates func() int) {
+	t.Helper()
+	sets, err := acl.ParseRuleSets([]byte(localPolicy))
+	if err != nil {
+		t.Fatalf("解析规则失败: %v", err)
+	}
+	policy = acl.NewPolicy(sets)
+	var mu sync.Mutex
+	n := 0
+	policy.Subscribe(func(context.Context) {
+		mu.Lock()
+		defer mu.Unlock()
+		n++
+	})
+	return policy, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return n
+	}
+}
+
+// ruleNames 返回策略中规则的名称
+func ruleNames(policy *acl.Policy) []string {
+	var rv []string
+	for _, r := range acl.Flatten(policy.RuleSets()) {
+		rv = append(rv, r.Name)
+	}
+	return rv
+}
+
+func expectRules(t *testing.T, policy *acl.Policy, want string) {
+	t.Helper()
+	if names := ruleNames(policy); len(names) != 1 || names[0] != want {
+		t.Fatalf("策略规则 = %v，期望 [%s]", names, want)
+	}
+}
+
+func TestPollAppliesPolicyAndWritesCache(t *testing.T) {
+	handler := &policyServer{}
+	handler.set(http.StatusOK, serverPolicy, `"v1"`)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	policy, _ := newPolicy(t)
+	cachePath := filepath.Join(t.TempDir(), "policy.yaml")
+	poller := remotepolicy.NewPoller(policy, server.URL,
+		remotepolicy.WithHTTPClient(server.Client()), remotepolicy.WithCachePath(cachePath))
+
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll 失败: %v", err)
+	}
+
+	expectRules(t, policy, "allow from server")
+	status := poller.Status()
+	if status.Source != remotepolicy.SourceServer || status.ETag != `"v1"` || status.Rules != 1 {
+		t.Errorf("状态 = %+v，期望来源 server、ETag \"v1\"、规则 1 条", status)
+	}
+	cached, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("读取缓存失败: %v", err)
+	}
+	if string(cached) != serverPolicy {
+		t.Errorf("缓存内容 = %q，期望 %q", cached, serverPolicy)
+	}
+}
+
+func TestPollNotModified(t *testing.T) {
+	handler := &policyServer{}
+	handler.set(http.StatusOK, serverPolicy, `"v1"`)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	policy, updates := newPolicy(t)
+	poller := remotepolicy.NewPoller(policy, server.URL, remotepolicy.WithHTTPClient(server.Client()))
+
+	for i := 0; i < 2; i++ {
+		if err := poller.Poll(context.Background()); err != nil {
+			t.Fatalf("第 %d 次 Poll 失败: %v", i+1, err)
+		}
+	}
+
+	if handler.notModified != 1 {
+		t.Fatalf("服务器返回 304 %d 次，期望第二次请求携带 If-None-Match 并返回 304", handler.notModified)
+	}
+	if n := updates(); n != 1 {
+		t.Fatalf("策略更新 %d 次，期望 304 时不重新下发", n)
+	}
+	if status := poller.Status(); status.ETag != `"v1"` || status.Error != "" {
+		t.Errorf("状态 = %+v，期望 ETag 不变且没有错误", status)
+	}
+}
+
+func TestPollKeepsPolicyOnServerError(t *testing.T) {
+	handler := &policyServer{}
+	handler.set(http.StatusOK, serverPolicy, `"v1"`)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	policy, updates := newPolicy(t)
+	poller := remotepolicy.NewPoller(policy, server.URL, remotepolicy.WithHTTPClient(server.Client()))
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll 失败: %v", err)
+	}
+
+	handler.set(http.StatusInternalServerError, "", "")
+	if err := poller.Poll(context.Background()); err == nil {
+		t.Fatal("Poll 成功，期望服务器返回 500 时失败")
+	}
+
+	expectRules(t, policy, "allow from server")
+	if n := updates(); n != 1 {
+		t.Fatalf("策略更新 %d 次，期望服务器错误时不更新", n)
+	}
+	if status := poller.Status(); status.Error == "" || status.Source != remotepolicy.SourceServer {
+		t.Errorf("状态 = %+v，期望记录错误并保留当前策略的来源", status)
+	}
+}
+
+func TestPollRejectsInvalidPolicy(t *testing.T) {
+	handler := &policyServer{}
+	handler.set(http.StatusOK, serverPolicy, `"v1"`)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	policy, updates := newPolicy(t)
+	cachePath := filepath.Join(t.TempDir(), "policy.yaml")
+	poller := remotepolicy.NewPoller(policy, server.URL,
+		remotepolicy.WithHTTPClient(server.Client()), remotepolicy.WithCachePath(cachePath))
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll 失败: %v", err)
+	}
+
+	handler.set(http.StatusOK, invalidPolicy, `"v2"`)
+	eventsBefore := len(audit.Events())
+	if err := poller.Poll(context.Background()); err == nil {
+		t.Fatal("Poll 成功，期望校验失败")
+	}
+
+	expectRules(t, policy, "allow from server")
+	if n := updates(); n != 1 {
+		t.Fatalf("策略更新 %d 次，期望校验失败时不更新", n)
+	}
+	events := audit.Events()[eventsBefore:]
+	if len(events) != 1 || events[0].Type != audit.PolicyRejected || events[0].Source != server.URL {
+		t.Fatalf("审计事件 = %+v，期望一条来源为 %s 的 %s 事件", events, server.URL, audit.PolicyRejected)
+	}
+	if cached, err := os.ReadFile(cachePath); err != nil || string(cached) != serverPolicy {
+		t.Errorf("缓存内容 = %q（%v），期望保留最近一次有效的策略", cached, err)
+	}
+}
+
+func TestStartFallsBackToCache(t *testing.T) {
+	handler := &policyServer{}
+	handler.set(http.StatusServiceUnavailable, "", "")
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(cachePath, []byte(serverPolicy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, _ := newPolicy(t)
+	poller := remotepolicy.NewPoller(policy, server.URL,
+		remotepolicy.WithHTTPClient(server.Client()), remotepolicy.WithCachePath(cachePath),
+		remotepolicy.WithInterval(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	poller.Start(ctx)
+
+	expectRules(t, policy, "allow from server")
+	if status := poller.Status(); status.Source != remotepolicy.SourceCache || status.ETag != "" {
+		t.Errorf("状态 = %+v，期望来源 cache 且没有 ETag", status)
+	}
+}
+
+func TestStartKeepsLocalPolicyWithoutCache(t *testing.T) {
+	handler := &policyServer{}
+	handler.set(http.StatusServiceUnavailable, "", "")
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	policy, updates := newPolicy(t)
+	poller := remotepolicy.NewPoller(policy, server.URL,
+		remotepolicy.WithHTTPClient(server.Client()), remotepolicy.WithCachePath(filepath.Join(t.TempDir(), "missing.yaml")),
+		remotepolicy.WithInterval(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	poller.Start(ctx)
+
+	expectRules(t, policy, "allow local")
+	if n := updates(); n != 0 {
+		t.Fatalf("策略更新 %d 次，期望保留本地配置的规则", n)
+	}
+	if status := poller.Status(); status.Source != "" {
+		t.Errorf("状态 = %+v，期望尚未加载任何策略", status)
+	}
+}
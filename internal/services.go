@@ -0,0 +1,124 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package internal
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ServiceConfig 端点提供的一个网络服务
+//
+// 字段说明:
+//   - Name: 网络服务名称
+//   - Labels: 该网络服务的端点标签，覆盖 NSM_LABELS 中的同名标签
+//   - RuleSets: 该网络服务的连接下发的规则集，为空时下发全部规则集
+//   - ConnectTo: 该网络服务的下一跳地址，未配置时为 NSM_CONNECT_TO
+type ServiceConfig struct {
+	Name      string
+	Labels    map[string]string
+	RuleSets  []string
+	ConnectTo url.URL
+}
+
+// serviceConfig 网络服务配置文件中一个网络服务的格式
+type serviceConfig struct {
+	Name      string            `yaml:"name"`
+	Labels    map[string]string `yaml:"labels"`
+	RuleSets  []string          `yaml:"ruleSets"`
+	ConnectTo string            `yaml:"connectTo"`
+}
+
+// ServiceNames 返回全部网络服务的名称
+func ServiceNames(services []ServiceConfig) []string {
+	names := make([]string, 0, len(services))
+	for i := range services {
+		names = append(names, services[i].Name)
+	}
+	return names
+}
+
+// retrieveServices 读取端点提供的网络服务
+// 未配置网络服务配置文件时，端点只提供 ServiceName 一个网络服务
+func retrieveServices(c *Config) error {
+	if c.ServicesConfigPath == "" {
+		c.Services = []ServiceConfig{{Name: c.ServiceName, ConnectTo: c.ConnectTo}}
+		return nil
+	}
+
+	raw, err := os.ReadFile(filepath.Clean(c.ServicesConfigPath))
+	if err != nil {
+		return errors.Wrapf(err, "cannot read services config %s", c.ServicesConfigPath)
+	}
+	services, err := parseServices(raw, c.ConnectTo)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse services config %s", c.ServicesConfigPath)
+	}
+	c.Services = services
+	return nil
+}
+
+// parseServices 解析网络服务配置文件，未配置下一跳的网络服务使用 connectTo
+//
+// 配置文件格式:
+//
+//	services:
+//	- name: secure-intranet
+//	  labels: {app: firewall-intranet}
+//	  ruleSets: [intranet]
+//	  connectTo: unix:///var/lib/networkservicemesh/nsm.io.sock
+func parseServices(raw []byte, connectTo url.URL) ([]ServiceConfig, error) {
+	var cfg struct {
+		Services []serviceConfig `yaml:"services"`
+	}
+	if err := yaml.UnmarshalStrict(raw, &cfg); err != nil {
+		return nil, errors.Wrap(err, "invalid services config")
+	}
+	if len(cfg.Services) == 0 {
+		return nil, errors.New("no services declared")
+	}
+
+	services := make([]ServiceConfig, 0, len(cfg.Services))
+	seen := make(map[string]bool, len(cfg.Services))
+	for _, s := range cfg.Services {
+		if s.Name == "" {
+			return nil, errors.New("service name must not be empty")
+		}
+		if seen[s.Name] {
+			return nil, errors.Errorf("duplicate service %q", s.Name)
+		}
+		seen[s.Name] = true
+
+		service := ServiceConfig{Name: s.Name, Labels: s.Labels, RuleSets: s.RuleSets, ConnectTo: connectTo}
+		if s.ConnectTo != "" {
+			u, err := url.Parse(s.ConnectTo)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid connectTo of service %q", s.Name)
+			}
+			service.ConnectTo = *u
+		}
+		services = append(services, service)
+	}
+	return services, nil
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package signature 校验策略包的分离签名
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// Suffix 分离签名文件相对于策略文件的后缀
+const Suffix = ".sig"
+
+// PEM 块类型
+const (
+	signatureBlock   = "SIGNATURE"
+	certificateBlock = "CERTIFICATE"
+	publicKeyBlock   = "PUBLIC KEY"
+)
+
+// Verifier 策略签名校验器
+//
+// 签名文件格式:
+//   - PEM 格式: 一个 SIGNATURE 块，以及可选的 CERTIFICATE 块（签名证书在前，其后为中间证书）
+//   - 或者只包含 base64 编码的签名
+//
+// 签名算法由公钥类型决定：Ed25519 直接签名原文；ECDSA（ASN.1 编码）和
+// RSA（PKCS #1 v1.5）签名原文的 SHA-256 摘要。
+//
+// 签名文件携带证书链时，证书链须能验证到配置的信任根，并用签名证书的公钥校验签名；
+// 否则依次尝试配置的公钥，任一公钥校验通过即可。
+type Verifier struct {
+	keys  []crypto.PublicKey
+	roots *x509.CertPool
+}
+
+// NewVerifier 从 PEM 文件加载公钥和信任根
+//
+// 参数:
+//   - keyFiles: PKIX 公钥文件路径列表（PEM 块类型 PUBLIC KEY，可包含多个公钥）
+//   - rootFiles: 信任根证书文件路径列表（PEM 块类型 CERTIFICATE）
+//
+// 返回:
+//   - *Verifier: 签名校验器；公钥和信任根都未配置时返回 nil，表示不校验签名
+//   - error: 文件读取或解析失败
+func NewVerifier(keyFiles, rootFiles []string) (*Verifier, error) {
+	if len(keyFiles) == 0 && len(rootFiles) == 0 {
+		return nil, nil
+	}
+	v := &Verifier{}
+	for _, path := range keyFiles {
+		blocks, err := readPEM(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range blocks {
+			if block.Type != publicKeyBlock {
+				continue
+			}
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, errors.Wrapf(err, "解析公钥 %s 失败", path)
+			}
+			v.keys = append(v.keys, key)
+		}
+	}
+	if len(rootFiles) > 0 {
+		v.roots = x509.NewCertPool()
+		for _, path := range rootFiles {
+			raw, err := os.ReadFile(filepath.Clean(path))
+			if err != nil {
+				return nil, errors.Wrapf(err, "读取信任根 %s 失败", path)
+			}
+			if !v.roots.AppendCertsFromPEM(raw) {
+				return nil, errors.Errorf("信任根 %s 中没有证书", path)
+			}
+		}
+	}
+	if len(v.keys) == 0 && v.roots == nil {
+		return nil, errors.New("公钥文件中没有 PUBLIC KEY 块")
+	}
+	return v, nil
+}
+
+// Verify 校验 data 的分离签名 sig
+//
+// 参数:
+//   - data: 策略内容
+//   - sig: 签名文件内容
+//
+// 返回:
+//   - error: 签名格式错误、证书链不可信或签名不匹配
+func (v *Verifier) Verify(data, sig []byte) error {
+	signature, chain, err := parseSignature(sig)
+	if err != nil {
+		return err
+	}
+
+	if len(chain) > 0 {
+		if v.roots == nil {
+			return errors.New("签名携带证书链，但未配置信任根")
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range chain[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err = chain[0].Verify(x509.VerifyOptions{
+			Roots:         v.roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if err != nil {
+			return errors.Wrap(err, "签名证书不可信")
+		}
+		return checkSignature(chain[0].PublicKey, data, signature)
+	}
+
+	if len(v.keys) == 0 {
+		return errors.New("签名未携带证书链，且未配置公钥")
+	}
+	for _, key := range v.keys {
+		if err = checkSignature(key, data, signature); err == nil {
+			return nil
+		}
+	}
+	return errors.Wrap(err, "没有公钥能校验签名")
+}
+
+// parseSignature 解析签名文件，返回签名和证书链（签名证书在前）
+func parseSignature(raw []byte) (signature []byte, chain []*x509.Certificate, err error) {
+	rest := raw
+	for {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		switch block.Type {
+		case signatureBlock:
+			signature = block.Bytes
+		case certificateBlock:
+			cert, parseErr := x509.ParseCertificate(block.Bytes)
+			if parseErr != nil {
+				return nil, nil, errors.Wrap(parseErr, "解析签名证书失败")
+			}
+			chain = append(chain, cert)
+		}
+	}
+	if signature == nil && len(chain) == 0 {
+		// 没有 PEM 块时按 base64 编码的签名处理
+		if signature, err = base64.StdEncoding.DecodeString(string(bytes.TrimSpace(raw))); err != nil {
+			return nil, nil, errors.Wrap(err, "签名既不是 PEM 格式也不是 base64 编码")
+		}
+	}
+	if len(signature) == 0 {
+		return nil, nil, errors.New("签名文件中没有签名")
+	}
+	return signature, chain, nil
+}
+
+// checkSignature 用公钥校验签名
+func checkSignature(key crypto.PublicKey, data, signature []byte) error {
+	digest := sha256.Sum256(data)
+	switch pub := key.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pub, data, signature) {
+			return errors.New("Ed25519 签名不匹配")
+		}
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, digest[:], signature) {
+			return errors.New("ECDSA 签名不匹配")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.Wrap(err, "RSA 签名不匹配")
+		}
+	default:
+		return errors.Errorf("不支持的公钥类型 %T", key)
+	}
+	return nil
+}
+
+// readPEM 读取 PEM 文件中的全部块
+func readPEM(path string) ([]*pem.Block, error) {
+	raw, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "读取 %s 失败", path)
+	}
+	var blocks []*pem.Block
+	for {
+		var block *pem.Block
+		if block, raw = pem.Decode(raw); block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
@@ -6,6 +6,7 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
@@ -16,11 +17,13 @@ import (
 	// 标准库和第三方工具
 	nested "github.com/antonfisher/nested-logrus-formatter"
 	"github.com/edwarnicke/grpcfd"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
 	"github.com/spiffe/go-spiffe/v2/workloadapi"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/types/known/emptypb"
 
 	// VPP相关
 	"github.com/networkservicemesh/vpphelper"
@@ -43,8 +46,11 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/recvfd"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/sendfd"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanismtranslation"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/null"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/passthrough"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/switchcase"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
 
 	// NSM SDK - 工具
@@ -61,15 +67,31 @@ import (
 	// 本地模块
 	"github.com/ifzzh/cmd-nse-template/internal"
 	"github.com/ifzzh/cmd-nse-template/internal/acl"
+	"github.com/ifzzh/cmd-nse-template/internal/admin"
+	"github.com/ifzzh/cmd-nse-template/internal/adminrpc"
+	"github.com/ifzzh/cmd-nse-template/internal/blocklist"
+	"github.com/ifzzh/cmd-nse-template/internal/drain"
+	"github.com/ifzzh/cmd-nse-template/internal/health"
+	"github.com/ifzzh/cmd-nse-template/internal/macip"
+	"github.com/ifzzh/cmd-nse-template/internal/remotepolicy"
 )
 
 func main() {
+	// 子命令：查询运行中实例的未命中规则报告，不启动NSE
+	if len(os.Args) > 1 && os.Args[1] == deadRulesCommand {
+		os.Exit(runDeadRules(os.Args[2:]))
+	}
+	// 子命令：通过管理gRPC接口查询运行中实例的连接和规则，不启动NSE
+	if len(os.Args) > 1 && os.Args[1] == connectionsCommand {
+		os.Exit(runConnections(os.Args[2:]))
+	}
+
 	// ========================================================================
 	// 阶段 0: 初始化 - 设置上下文和日志系统
 	// ========================================================================
 
-	// 设置信号捕获上下文（用于优雅退出）
-	ctx, cancel := notifyContext()
+	// 设置运行上下文：取消时停止VPP、gRPC服务器和后台任务
+	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// 配置日志系统
@@ -82,6 +104,10 @@ func main() {
 		log.FromContext(ctx).Infof("%s", err)
 	}
 
+	// 设置信号捕获上下文（用于优雅退出）：收到退出信号后按顺序关闭（见 shutdown），运行上下文取消时同样触发
+	signalCtx, stopSignals := notifyContext(ctx)
+	defer stopSignals()
+
 	// 打印启动流程说明
 	log.FromContext(ctx).Infof("========================================")
 	log.FromContext(ctx).Infof("防火墙网络服务端点 (Firewall NSE) 启动")
@@ -146,11 +172,26 @@ func main() {
 		log.FromContext(ctx).Infof("pprof性能分析已启用，监听地址: %s", config.PprofListenOn)
 	}
 
+	// 配置健康检查：启动完成、VPP已连接、策略已加载且有效、端点已注册后就绪；VPP异常退出或策略同步停滞时存活检查失败
+	// HTTP探针和gRPC健康检查服务在启动早期开始监听，/readyz 中的 startup 检查给出当前所处的启动阶段
+	healthChecker := health.NewChecker()
+	startup := health.NewCondition(errors.New("启动中: 阶段1 加载配置"))
+	vppConnected := health.NewCondition(errors.New("VPP 未连接"))
+	vppAlive := health.NewCondition(nil)
+	healthChecker.AddReadinessCheck("startup", startup.Check)
+	healthChecker.AddReadinessCheck("vpp-connection", vppConnected.Check)
+	healthChecker.AddLivenessCheck("vpp", vppAlive.Check)
+	if config.HealthEnabled {
+		go health.ListenAndServe(ctx, config.HealthListenOn, healthChecker.Handler())
+		go healthChecker.ServeGRPC(ctx, config.HealthGRPCListenOn)
+	}
+
 	// ========================================================================
 	// 阶段 2: 身份认证 - 获取SPIFFE身份凭证（SVID）
 	// ========================================================================
 	log.FromContext(ctx).Infof("")
 	log.FromContext(ctx).Infof(">>> 阶段2: 获取SPIFFE身份凭证")
+	startup.Set(errors.New("启动中: 阶段2 获取SPIFFE身份凭证"))
 	log.FromContext(ctx).Infof("提示: 如果程序停在此处，请检查SPIRE Agent日志")
 	// 创建X509身份凭证源（连接SPIRE Agent）
 	source, err := workloadapi.NewX509Source(ctx)
@@ -179,6 +220,7 @@ func main() {
 	// ========================================================================
 	log.FromContext(ctx).Infof("")
 	log.FromContext(ctx).Infof(">>> 阶段3: 创建gRPC客户端选项")
+	startup.Set(errors.New("启动中: 阶段3 创建gRPC客户端选项"))
 	// 配置gRPC客户端选项（用于连接NSM Manager和其他NSE）
 	clientOptions := append(
 		tracing.WithTracingDial(),                                                                                      // 启用追踪
@@ -200,28 +242,120 @@ func main() {
 	// ========================================================================
 	log.FromContext(ctx).Infof("")
 	log.FromContext(ctx).Infof(">>> 阶段4: 创建防火墙网络服务端点")
+	startup.Set(errors.New("启动中: 阶段4 创建防火墙网络服务端点"))
 
 	// 启动VPP进程并建立连接
 	log.FromContext(ctx).Infof("正在启动VPP (Vector Packet Processing)...")
 	vppConn, vppErrCh := vpphelper.StartAndDialContext(ctx)
-	exitOnErr(ctx, cancel, vppErrCh) // 监控VPP错误通道
+	vppErrCh = notifyOnErr(vppErrCh, vppAlive) // VPP异常退出时存活检查失败
+	exitOnErr(ctx, cancel, vppErrCh)           // 监控VPP错误通道
+	vppConnected.Set(nil)
 	log.FromContext(ctx).Infof("VPP连接建立成功")
 
+	// ACL控制器：供管理gRPC接口查询连接及其ACL，并在运行时修改规则（修改写入状态文件，重启后恢复）
+	// 配置了策略签名时启动不加载状态文件，运行时的修改重启后会丢失，因此拒绝运行时修改
+	aclControllerOptions := []acl.ControllerOption{acl.WithStateFile(config.ACLStatePath, config.ACLConfigDigest)}
+	if config.PolicyVerifier != nil {
+		aclControllerOptions = append(aclControllerOptions, acl.WithReadOnlyRules("已配置策略签名校验，规则只能通过签名的配置文件修改"))
+	}
+	aclController := acl.NewController(aclControllerOptions...)
+
+	// 配置ACL编程重试策略（瞬时VPP API错误时回滚并重试）和ACL在接口列表中的位置
+	// 端点注册：紧急模式生效时在注册的NSE标签中标记当前模式
+	registration := internal.NewRegistration(config.Labels,
+		internal.WithExpiration(config.RegistrationTTL),
+		internal.WithMaxBackoff(config.RegistrationBackoff),
+	)
+	emergencyLabelUpdates := make(chan struct{}, 1)
+	aclOptions := []acl.Option{
+		acl.WithRetryPolicy(acl.RetryPolicy{
+			MaxAttempts: config.ACLRetryMaxAttempts,
+			Backoff:     config.ACLRetryBackoff,
+			MaxBackoff:  config.ACLRetryMaxBackoff,
+			CallTimeout: config.ACLRetryCallTimeout,
+		}),
+		acl.WithPosition(config.ACLPosition),
+		acl.WithChunkSize(config.ACLChunkSize),
+		acl.WithDegradedRetryInterval(config.ACLFailOpenRetry),
+		acl.WithController(aclController),
+		acl.WithEmergencyListener(func(context.Context, acl.EmergencyMode) {
+			// 异步发布，紧急模式切换不等待注册中心（见 publishEmergencyLabels）
+			select {
+			case emergencyLabelUpdates <- struct{}{}:
+			default:
+			}
+		}),
+	}
+	if config.ACLAntiSpoof {
+		aclOptions = append(aclOptions, acl.WithAntiSpoof())
+	}
+	if config.ACLQuarantineRuleSet != "" {
+		aclOptions = append(aclOptions, acl.WithQuarantineRuleSet(config.ACLQuarantineRuleSet))
+	}
+	// 按请求的网络服务选择规则集（只对配置了规则集的网络服务生效）
+	if serviceRuleSets := serviceRuleSets(config.Services); len(serviceRuleSets) > 0 {
+		aclOptions = append(aclOptions, acl.WithServiceRuleSets(serviceRuleSets))
+	}
+
+	// 防火墙策略：配置的规则（配置了策略服务器时由轮询获取的规则替换）、状态文件中尚未到期的临时规则，以及IP黑名单文件编译成的拒绝规则（文件变化时更新已有连接）
+	policy := acl.NewPolicy(config.ACLRuleSets)
+	if len(config.ACLTemporaryRules) > 0 {
+		policy.SetTemporaryRules(ctx, config.ACLTemporaryRules)
+	}
+	adminOptions := []admin.Option{admin.WithAuditEvents(), admin.WithRegistration(registration)}
+	var poller *remotepolicy.Poller
+	if config.ACLPolicyURL != "" {
+		poller = remotepolicy.NewPoller(policy, config.ACLPolicyURL,
+			remotepolicy.WithInterval(config.ACLPolicyPollInterval),
+			remotepolicy.WithCachePath(config.ACLPolicyCachePath),
+			remotepolicy.WithHTTPClient(&http.Client{Timeout: config.ACLPolicyTimeout}),
+			remotepolicy.WithVerifier(config.PolicyVerifier),
+		)
+		poller.Start(ctx)
+		adminOptions = append(adminOptions, admin.WithPolicyPoller(poller))
+		log.FromContext(ctx).Infof("策略服务器已启用: %s，轮询间隔: %v", config.ACLPolicyURL, config.ACLPolicyPollInterval)
+	}
+	if len(config.ACLBlocklistFiles) > 0 {
+		blocklists := blocklist.Watch(ctx, policy, config.ACLBlocklistFiles)
+		adminOptions = append(adminOptions, admin.WithBlocklists(blocklists))
+		log.FromContext(ctx).Infof("IP黑名单已启用，黑名单文件: %v", config.ACLBlocklistFiles)
+	}
+
+	// 配置MACIP源地址防伪造（未开启时使用空元素）
+	macipElement := null.NewServer()
+	if config.MACIPEnabled {
+		macipElement = macip.NewServer(vppConn)
+	}
+
+	// 配置ACL规则命中计数（VPP ACL接口计数 + OpenTelemetry指标）
+	if config.ACLCountersEnabled {
+		counters := acl.NewRuleCounters(ctx, vppConn, config.VPPStatsSocket, config.ACLCountersInterval)
+		aclOptions = append(aclOptions, acl.WithRuleCounters(counters))
+		adminOptions = append(adminOptions,
+			admin.WithRuleCounters(counters),
+			admin.WithDeadRules(counters, config.ACLDeadRuleWindow),
+		)
+		log.FromContext(ctx).Infof("ACL规则命中计数已启用，采集周期: %v", config.ACLCountersInterval)
+	}
+
 	// 构建防火墙端点链（服务器端）
 	log.FromContext(ctx).Infof("正在构建防火墙端点链...")
+	drainServer := drain.NewServer()
 	firewallEndpoint := new(struct{ endpoint.Endpoint })
 	firewallEndpoint.Endpoint = endpoint.NewServer(ctx,
 		spiffejwt.TokenGeneratorFunc(source, config.MaxTokenLifetime), // Token生成器
 		endpoint.WithName(config.Name),                                 // 端点名称
 		endpoint.WithAuthorizeServer(authorize.NewServer()),            // 授权服务器
 		endpoint.WithAdditionalFunctionality(
+			drainServer, // 活动连接跟踪，关闭时拒绝新请求并关闭全部连接
 			// 基础功能链（接收/发送文件描述符、接口管理、交叉连接等）
 			recvfd.NewServer(),                           // 接收文件描述符
 			sendfd.NewServer(),                           // 发送文件描述符
 			up.NewServer(ctx, vppConn),                   // VPP接口UP状态管理
-			clienturl.NewServer(&config.ConnectTo),       // 客户端连接URL
+			serviceConnectTo(config.Services),            // 按请求的网络服务选择下一跳连接URL
 			xconnect.NewServer(vppConn),                  // VPP交叉连接（L2转发）
-			acl.NewServer(vppConn, config.ACLConfig),     // ACL防火墙规则应用 ← 核心功能
+			macipElement,                                 // MACIP源地址防伪造（可选）
+			acl.NewServer(vppConn, policy, aclOptions...), // ACL防火墙规则应用 ← 核心功能
 			mechanisms.NewServer(map[string]networkservice.NetworkServiceServer{
 				memif.MECHANISM: chain.NewNetworkServiceServer(memif.NewServer(ctx, vppConn)), // memif共享内存接口
 			}),
@@ -247,11 +381,27 @@ func main() {
 		))
 	log.FromContext(ctx).Infof("防火墙端点链构建完成（包含ACL规则）")
 
+	// 策略和端点注册的健康检查
+	addHealthChecks(healthChecker, config, aclController, poller, registration)
+
+	// 紧急模式信号：SIGRTMIN+1 切换封锁模式（deny-all），SIGRTMIN+2 切换应急放行模式（allow-all）
+	go handleEmergencySignals(ctx, aclController)
+	go publishEmergencyLabels(ctx, emergencyLabelUpdates, aclController, registration)
+
+	// 重新加载配置信号：SIGHUP 重新加载配置，ACL规则、日志级别和端点标签立即生效
+	go handleReload(ctx, config, aclController, registration)
+
+	// 配置管理端点（规则命中计数查询等）
+	if config.AdminEnabled {
+		go admin.ListenAndServe(ctx, config.AdminListenOn, admin.NewHandler(adminOptions...))
+	}
+
 	// ========================================================================
 	// 阶段 5: gRPC服务器创建 - 创建服务器并注册防火墙端点
 	// ========================================================================
 	log.FromContext(ctx).Infof("")
 	log.FromContext(ctx).Infof(">>> 阶段5: 创建gRPC服务器并挂载端点")
+	startup.Set(errors.New("启动中: 阶段5 创建gRPC服务器并挂载端点"))
 	// 创建gRPC服务器（启用追踪和mTLS）
 	server := grpc.NewServer(append(
 		tracing.WithTracing(),                                      // 启用分布式追踪
@@ -280,27 +430,47 @@ func main() {
 	exitOnErr(ctx, cancel, srvErrCh) // 监控服务器错误通道
 	log.FromContext(ctx).Infof("gRPC服务器启动成功")
 
+	// 启动管理gRPC接口（独立的本地socket，按SPIFFE ID鉴权）
+	if config.AdminGRPCEnabled {
+		allowedIDs, parseErr := adminrpc.ParseIDs(config.AdminGRPCAllowedIDs)
+		if parseErr != nil {
+			log.FromContext(ctx).Fatalf("管理gRPC接口配置错误: %+v", parseErr)
+		}
+		if len(allowedIDs) == 0 {
+			allowedIDs = append(allowedIDs, svid.ID)
+		}
+		adminErrCh := grpcutils.ListenAndServe(ctx, &config.AdminGRPCListenOn, adminrpc.NewServer(source, allowedIDs, aclController))
+		go func() {
+			for adminErr := range adminErrCh {
+				log.FromContext(ctx).Errorf("管理gRPC接口错误: %+v", adminErr)
+			}
+		}()
+		log.FromContext(ctx).Infof("管理gRPC接口已启用，监听地址: %s，允许的SPIFFE ID: %v", config.AdminGRPCListenOn.String(), allowedIDs)
+	}
+
 	// ========================================================================
 	// 阶段 6: 端点注册 - 向NSM Manager注册防火墙网络服务端点
 	// ========================================================================
 	log.FromContext(ctx).Infof("")
 	log.FromContext(ctx).Infof(">>> 阶段6: 向NSM Manager注册端点")
+	startup.Set(errors.New("启动中: 阶段6 向NSM Manager注册端点"))
 
 	// 创建Registry客户端
 	nseRegistryClient := internal.NewRegistryClient(ctx, &config.ConnectTo, clientOptions, config.RegistryClientPolicies)
 
-	// 注册网络服务端点
-	nse, err := internal.RegisterEndpoint(ctx, nseRegistryClient, config.Name, config.ServiceName, config.Labels, listenOn.String())
+	// 注册网络服务端点，注册监督在到期前刷新注册，失败时在后台按退避重新注册
+	nse, err := registration.Register(ctx, nseRegistryClient, config.Name, config.Services, listenOn.String())
 	if err != nil {
-		log.FromContext(ctx).Fatalf("端点注册失败: %+v", err)
+		log.FromContext(ctx).Errorf("端点注册失败，将在后台重试: %+v", err)
+	} else {
+		log.FromContext(ctx).Infof("端点注册成功!")
+		log.FromContext(ctx).Infof("  名称: %s", nse.Name)
+		log.FromContext(ctx).Infof("  服务: %v", nse.NetworkServiceNames)
+		log.FromContext(ctx).Infof("  地址: %s", nse.Url)
+		log.FromContext(ctx).Infof("  标签: %+v", config.Labels)
+		log.FromContext(ctx).Infof("  注册到期: %v", registration.Status().ExpiresAt)
 	}
 
-	log.FromContext(ctx).Infof("端点注册成功!")
-	log.FromContext(ctx).Infof("  名称: %s", nse.Name)
-	log.FromContext(ctx).Infof("  服务: %v", nse.NetworkServiceNames)
-	log.FromContext(ctx).Infof("  地址: %s", nse.Url)
-	log.FromContext(ctx).Infof("  标签: %+v", config.Labels)
-
 	// ========================================================================
 	// 启动完成 - 输出启动统计并进入运行状态
 	// ========================================================================
@@ -308,13 +478,14 @@ func main() {
 	log.FromContext(ctx).Infof("========================================")
 	log.FromContext(ctx).Infof("✓ 启动成功! 耗时: %v", time.Since(starttime))
 	log.FromContext(ctx).Infof("========================================")
+	startup.Set(nil)
 	log.FromContext(ctx).Infof("防火墙NSE正在运行，等待连接请求...")
 
 	// ========================================================================
 	// 等待退出信号 - 保持运行直到收到中断信号
 	// ========================================================================
-	<-ctx.Done()   // 等待上下文取消（SIGINT/SIGTERM等）
-	<-vppErrCh     // 等待VPP错误通道关闭
+	<-signalCtx.Done() // 等待退出信号（SIGINT/SIGTERM等）或运行上下文取消
+	shutdown(ctx, config.ShutdownTimeout, registration, drainServer, server, cancel, vppErrCh)
 	log.FromContext(ctx).Infof("防火墙NSE已停止")
 }
 
@@ -332,21 +503,126 @@ func exitOnErr(ctx context.Context, cancel context.CancelFunc, errCh <-chan erro
 	default:
 	}
 
-	// 在后台等待错误（阻塞）
+	// 在后台等待错误（阻塞），通道关闭（如关闭时停止服务器）时不记录错误
 	go func(ctx context.Context, errCh <-chan error) {
-		err := <-errCh
-		log.FromContext(ctx).Error(err)
+		if err := <-errCh; err != nil {
+			log.FromContext(ctx).Error(err)
+		}
 		cancel() // 触发上下文取消，优雅退出
 	}(ctx, errCh)
 }
 
+// serviceConnectTo 创建按请求的网络服务设置下一跳连接URL的链式元素
+// 每个网络服务使用各自的 ConnectTo，请求未声明的网络服务时返回错误
+func serviceConnectTo(services []internal.ServiceConfig) networkservice.NetworkServiceServer {
+	cases := make([]*switchcase.ServerCase, 0, len(services))
+	for i := range services {
+		name := services[i].Name
+		cases = append(cases, &switchcase.ServerCase{
+			Condition: func(_ context.Context, conn *networkservice.Connection) bool {
+				return conn.GetNetworkService() == name
+			},
+			Server: clienturl.NewServer(&services[i].ConnectTo),
+		})
+	}
+	cases = append(cases, &switchcase.ServerCase{
+		Condition: func(context.Context, *networkservice.Connection) bool { return true },
+		Server:    unknownServiceServer{services: internal.ServiceNames(services)},
+	})
+	return switchcase.NewServer(cases...)
+}
+
+// unknownServiceServer 拒绝请求未声明的网络服务的连接
+type unknownServiceServer struct {
+	services []string
+}
+
+// Request 返回错误，不继续调用端点链
+func (s unknownServiceServer) Request(_ context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return nil, errors.Errorf("网络服务 %q 未在本端点声明，可用的网络服务: %v", request.GetConnection().GetNetworkService(), s.services)
+}
+
+// Close 继续调用端点链，释放可能残留的资源
+func (s unknownServiceServer) Close(ctx context.Context, conn *networkservice.Connection) (*emptypb.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+// serviceRuleSets 返回配置了规则集的网络服务到其规则集名称的映射
+func serviceRuleSets(services []internal.ServiceConfig) map[string][]string {
+	rv := make(map[string][]string)
+	for i := range services {
+		if len(services[i].RuleSets) > 0 {
+			rv[services[i].Name] = services[i].RuleSets
+		}
+	}
+	return rv
+}
+
+// emergencyLabel 紧急模式生效时注册的NSE标签键，值为当前模式
+const emergencyLabel = "emergency-mode"
+
+// emergencyLabelTimeout 发布一次紧急模式标签的超时
+const emergencyLabelTimeout = 10 * time.Second
+
+// publishEmergencyLabels 收到通知时将当前紧急模式发布为注册的NSE标签，ctx 结束时停止
+// 发布期间的多次切换合并为一次，发布读取的是最新的模式；注册中心不可用时发布超时失败，标签由注册监督在下一次注册时带上
+func publishEmergencyLabels(ctx context.Context, updates <-chan struct{}, controller *acl.Controller, registration *internal.Registration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-updates:
+		}
+		value := string(controller.EmergencyStatus().Mode)
+		if value == string(acl.EmergencyOff) {
+			value = ""
+		}
+		labelCtx, cancelLabel := context.WithTimeout(ctx, emergencyLabelTimeout)
+		if err := registration.SetLabel(labelCtx, emergencyLabel, value); err != nil {
+			log.FromContext(ctx).Errorf("更新端点紧急模式标签失败: %+v", err)
+		}
+		cancelLabel()
+	}
+}
+
+// 紧急模式切换信号（SIGUSR1/SIGUSR2 已用于切换日志级别），Linux 上 SIGRTMIN 为 34
+const (
+	signalDenyAll  = syscall.Signal(35) // SIGRTMIN+1
+	signalAllowAll = syscall.Signal(36) // SIGRTMIN+2
+)
+
+// handleEmergencySignals 按信号切换全局紧急模式，ctx 结束时停止
+// SIGRTMIN+1 在封锁模式（deny-all）与正常模式之间切换，SIGRTMIN+2 在应急放行模式（allow-all）与正常模式之间切换
+func handleEmergencySignals(ctx context.Context, controller *acl.Controller) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, signalDenyAll, signalAllowAll)
+	defer signal.Stop(sigCh)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-sigCh:
+			mode := acl.EmergencyDenyAll
+			if sig == signalAllowAll {
+				mode = acl.EmergencyAllowAll
+			}
+			if controller.EmergencyStatus().Mode == mode {
+				mode = acl.EmergencyOff
+			}
+			log.FromContext(ctx).Warnf("收到信号 %v，切换紧急模式: %s", sig, mode)
+			if _, err := controller.SetEmergencyMode(ctx, mode); err != nil {
+				log.FromContext(ctx).Errorf("切换紧急模式失败: %+v", err)
+			}
+		}
+	}
+}
+
 // notifyContext 创建一个可以响应系统信号的上下文
-// 支持的信号：SIGINT（Ctrl+C）、SIGHUP、SIGTERM、SIGQUIT
-func notifyContext() (context.Context, context.CancelFunc) {
+// 支持的信号：SIGINT（Ctrl+C）、SIGTERM、SIGQUIT；SIGHUP 用于重新加载配置（见 handleReload）
+func notifyContext(parent context.Context) (context.Context, context.CancelFunc) {
 	return signal.NotifyContext(
-		context.Background(),
+		parent,
 		os.Interrupt,       // Ctrl+C
-		syscall.SIGHUP,     // 终端挂起
 		syscall.SIGTERM,    // 终止信号（graceful shutdown）
 		syscall.SIGQUIT,    // 退出信号
 	)
@@ -0,0 +1,76 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package main
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ifzzh/cmd-nse-template/internal"
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+	"github.com/ifzzh/cmd-nse-template/internal/health"
+	"github.com/ifzzh/cmd-nse-template/internal/remotepolicy"
+)
+
+// addHealthChecks 添加策略和端点注册的健康检查
+//
+// 就绪检查:
+//   - policy: 生效的策略已加载且通过校验：配置文件中的规则已加载，或已从策略服务器（或其缓存）获取策略
+//   - registration: 端点已注册且注册未到期（见 Registration.Ready）
+//
+// 存活检查:
+//   - acl-reconcile: 策略变化后重新下发全部连接的 ACL 持续时间未超过 config.HealthReconcileTimeout
+func addHealthChecks(checker *health.Checker, config *internal.Config, controller *acl.Controller,
+	poller *remotepolicy.Poller, registration *internal.Registration) {
+	checker.AddReadinessCheck("policy", func() error {
+		if poller != nil && poller.Status().Source != "" {
+			return nil
+		}
+		if !controller.ConfigLoaded() {
+			return errors.Errorf("ACL 策略未加载: 配置文件 %s 读取或校验失败", config.ACLConfigPath)
+		}
+		return nil
+	})
+	checker.AddReadinessCheck("registration", registration.Ready)
+	checker.AddLivenessCheck("acl-reconcile", func() error {
+		if d := controller.ReconcileDuration(); d > config.HealthReconcileTimeout {
+			return errors.Errorf("策略同步已持续 %v，超过 %v", d.Round(time.Second), config.HealthReconcileTimeout)
+		}
+		return nil
+	})
+}
+
+// notifyOnErr 转发 errCh 中的错误，收到错误时将 condition 置为失败；errCh 关闭时关闭返回的通道
+// 用于 VPP 错误通道：VPP 异常退出后存活检查失败
+func notifyOnErr(errCh <-chan error, condition *health.Condition) <-chan error {
+	rv := make(chan error, 1)
+	go func() {
+		defer close(rv)
+		for err := range errCh {
+			condition.Set(errors.Wrap(err, "VPP 异常退出"))
+			select {
+			case rv <- err:
+			default:
+			}
+		}
+	}()
+	return rv
+}
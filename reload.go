@@ -0,0 +1,129 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ifzzh/cmd-nse-template/internal"
+	"github.com/ifzzh/cmd-nse-template/internal/acl"
+)
+
+// reloadLabelTimeout 重新加载配置时更新端点标签（重新注册）的超时
+const reloadLabelTimeout = 10 * time.Second
+
+// handleReload 收到 SIGHUP 时重新加载配置，ctx 结束时停止
+//
+// 重新执行 internal.ReloadConfig：ACL 规则、日志级别和端点标签的变化立即生效，
+// 其他配置项的变化记录警告，重启后生效。加载或应用失败时保持当前配置。
+func handleReload(ctx context.Context, config *internal.Config, controller *acl.Controller, registration *internal.Registration) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	// current 为当前生效的配置：只有已应用的变化才会更新，需要重启的变化在每次重新加载时都会提示
+	current := *config
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			reloadConfig(ctx, &current, controller, registration)
+		}
+	}
+}
+
+// reloadConfig 重新加载配置并将可以在运行时生效的变化应用到 current
+func reloadConfig(ctx context.Context, current *internal.Config, controller *acl.Controller, registration *internal.Registration) {
+	logger := log.FromContext(ctx).WithField("reload", "config")
+	logger.Infof("收到 SIGHUP，重新加载配置")
+
+	reloaded, err := internal.ReloadConfig(ctx)
+	if err != nil {
+		logger.Errorf("重新加载配置失败，保持当前配置: %+v", err)
+		return
+	}
+
+	for _, name := range current.ChangedFields(reloaded) {
+		switch name {
+		case "LogLevel":
+			level, levelErr := logrus.ParseLevel(reloaded.LogLevel)
+			if levelErr != nil {
+				logger.Errorf("无效的日志级别 %s，保持当前日志级别 %s", reloaded.LogLevel, current.LogLevel)
+				continue
+			}
+			logrus.SetLevel(level)
+			current.LogLevel = reloaded.LogLevel
+			logger.Infof("日志级别已更新: %s", level)
+		case "Labels":
+			labelCtx, cancelLabel := context.WithTimeout(ctx, reloadLabelTimeout)
+			labelErr := registration.UpdateLabels(labelCtx, labelChanges(current.Labels, reloaded.Labels))
+			cancelLabel()
+			if labelErr != nil {
+				logger.Errorf("更新端点标签失败: %+v", labelErr)
+				continue
+			}
+			current.Labels = reloaded.Labels
+			logger.Infof("端点标签已更新: %+v", reloaded.Labels)
+		default:
+			logger.Warnf("配置项 %s 已变化，需要重启后生效", name)
+		}
+	}
+
+	switch {
+	case reloaded.ACLConfigDigest == "":
+		logger.Errorf("ACL 配置文件 %s 读取或校验失败，保持当前规则", reloaded.ACLConfigPath)
+	case reloaded.ACLConfigDigest == current.ACLConfigDigest:
+		logger.Infof("ACL 配置文件未变化")
+	case current.ACLPolicyURL != "":
+		logger.Warnf("ACL 配置文件已变化，但规则由策略服务器 %s 下发，配置文件中的规则不生效", current.ACLPolicyURL)
+	default:
+		rules := &acl.ConfiguredRules{RuleSets: reloaded.ACLRuleSets, Temporary: reloaded.ACLTemporaryRules}
+		if ruleErr := controller.ReloadConfig(ctx, reloaded.ACLConfigDigest, rules, true); ruleErr != nil {
+			logger.Errorf("应用重新加载的 ACL 规则失败，保持当前规则: %+v", ruleErr)
+			break
+		}
+		current.ACLConfigDigest = reloaded.ACLConfigDigest
+		logger.Infof("ACL 规则已更新: %d 个规则集", len(reloaded.ACLRuleSets))
+	}
+}
+
+// labelChanges 返回从 current 变为 next 需要设置的标签，被删除的标签值为空
+func labelChanges(current, next map[string]string) map[string]string {
+	changes := make(map[string]string, len(next))
+	for key, value := range next {
+		if current[key] != value {
+			changes[key] = value
+		}
+	}
+	for key := range current {
+		if _, ok := next[key]; !ok {
+			changes[key] = ""
+		}
+	}
+	return changes
+}
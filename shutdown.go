@@ -0,0 +1,83 @@
+// Copyright (c) 2026 OpenInfra Foundation Europe. All rights reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"google.golang.org/grpc"
+
+	"github.com/ifzzh/cmd-nse-template/internal"
+	"github.com/ifzzh/cmd-nse-template/internal/drain"
+)
+
+// shutdown 按顺序关闭防火墙端点，记录每个步骤及其耗时
+//
+// 关闭顺序:
+//  1. 从注册中心注销端点，NSM 不再为新连接选择该端点
+//  2. 停止接受新的 Request，关闭全部活动连接（链中各元素释放资源，如删除 ACL）
+//  3. 停止 gRPC 服务器
+//  4. 取消运行上下文，等待 VPP 退出
+//
+// 步骤 1 和 2 共用 timeout 截止时间，到期时跳过未完成的部分继续关闭。
+func shutdown(ctx context.Context, timeout time.Duration, registration *internal.Registration, drainServer *drain.Server,
+	server *grpc.Server, cancel context.CancelFunc, vppErrCh <-chan error) {
+	logger := log.FromContext(ctx).WithField("shutdown", "firewall")
+	logger.Infof("开始关闭防火墙NSE，注销端点和关闭连接的截止时间: %v", timeout)
+	start := time.Now()
+
+	// 运行上下文可能已被取消（如VPP异常退出），关闭步骤只继承其中的值
+	deadlineCtx, cancelDeadline := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancelDeadline()
+
+	shutdownStep(deadlineCtx, "1/4 注销端点", func() error {
+		return registration.Unregister(deadlineCtx)
+	})
+	shutdownStep(deadlineCtx, "2/4 停止接受请求并关闭活动连接", func() error {
+		closed, err := drainServer.Shutdown(deadlineCtx)
+		logger.Infof("已关闭 %d 个活动连接", closed)
+		return err
+	})
+	shutdownStep(deadlineCtx, "3/4 停止gRPC服务器", func() error {
+		server.Stop()
+		return nil
+	})
+	shutdownStep(deadlineCtx, "4/4 停止VPP", func() error {
+		cancel()
+		<-vppErrCh
+		return nil
+	})
+
+	logger.Infof("防火墙NSE关闭完成，总耗时: %v", time.Since(start))
+}
+
+// shutdownStep 执行一个关闭步骤并记录耗时，失败时记录错误后继续
+func shutdownStep(ctx context.Context, name string, fn func() error) {
+	logger := log.FromContext(ctx).WithField("shutdown", name)
+	start := time.Now()
+	logger.Infof("关闭步骤开始")
+	if err := fn(); err != nil {
+		logger.Errorf("关闭步骤失败，耗时 %v: %+v", time.Since(start), err)
+		return
+	}
+	logger.Infof("关闭步骤完成，耗时 %v", time.Since(start))
+}